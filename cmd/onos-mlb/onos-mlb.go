@@ -9,7 +9,9 @@ import (
 
 	"github.com/onosproject/onos-lib-go/pkg/certs"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-mlb/pkg/controller"
 	"github.com/onosproject/onos-mlb/pkg/manager"
+	"github.com/onosproject/onos-mlb/pkg/monitor"
 )
 
 var log = logging.GetLogger()
@@ -25,9 +27,49 @@ func main() {
 	grpcPort := flag.Int("grpcPort", 5150, "grpc Port number")
 	overloadThreshold := flag.Int("overloadThreshold", 100, "Overload threshold")
 	targetLoadThreshold := flag.Int("targetLoadThreshold", 0, "Target load threshold")
+	targetHysteresis := flag.Int("targetHysteresis", 0, "Hysteresis margin around the target load threshold")
+	overloadHysteresis := flag.Int("overloadHysteresis", 0, "Hysteresis margin around the overload threshold")
+	timeToTrigger := flag.Int("timeToTrigger", controller.DefaultTimeToTrigger, "Number of consecutive cycles a load condition should hold before Ocn is changed")
+	pingPongWindow := flag.Int("pingPongWindow", controller.DefaultPingPongWindow, "Time window in seconds to detect Ocn ping-pong")
+	pingPongCount := flag.Int("pingPongCount", controller.DefaultPingPongCount, "Number of Ocn direction changes in the window to detect ping-pong; 0 disables detection")
+	pingPongCooldown := flag.Int("pingPongCooldown", controller.DefaultPingPongCooldown, "Time in seconds for which Ocn of a ping-pong pair is frozen")
+	pidKp := flag.Int("pidKp", controller.DefaultPIDKp, "Proportional gain of the pid algorithm in hundredths")
+	pidKi := flag.Int("pidKi", controller.DefaultPIDKi, "Integral gain of the pid algorithm in hundredths")
+	pidKd := flag.Int("pidKd", controller.DefaultPIDKd, "Derivative gain of the pid algorithm in hundredths")
+	pidIntegralLimit := flag.Int("pidIntegralLimit", controller.DefaultPIDIntegralLimit, "Limit of the accumulated load error of the pid algorithm")
+	pairMode := flag.String("pairMode", "none", "Ocn coordination of neighbor pairs: none, antisymmetric or bounded")
+	pairSumBound := flag.Int("pairSumBound", 0, "Largest |Ocn(A->B) + Ocn(B->A)| in Ocn steps for the bounded pair mode")
+	globalMigrationRate := flag.Int("globalMigrationRate", controller.DefaultGlobalMigrationRate, "Share of UEs in percent assumed to move per Ocn step in the global algorithm")
+	loadMetric := flag.String("loadMetric", "share", "Load metric: share of all UEs (share), UEs over the cell's own capacity (capacity), or weighted UE load and PRB usage (composite)")
+	loadWeightUEs := flag.Int("loadWeightUEs", controller.DefaultLoadWeightUEs, "Weight of the UE load in the composite load metric")
+	loadWeightPRBDL := flag.Int("loadWeightPRBDL", controller.DefaultLoadWeightPRBDL, "Weight of the downlink PRB usage in the composite load metric")
+	loadWeightPRBUL := flag.Int("loadWeightPRBUL", controller.DefaultLoadWeightPRBUL, "Weight of the uplink PRB usage in the composite load metric")
+	defaultMaxUEs := flag.Int("defaultMaxUEs", 0, "Capacity in UEs of the cells whose capacity is neither configured nor known in R-NIB for the capacity load metric")
+	maxUEsPer100PRBs := flag.Int("maxUEsPer100PRBs", 0, "Capacity in UEs per 100 PRBs to derive the cell capacity from its bandwidth in R-NIB; 0 disables it")
+	staleGracePeriod := flag.Int("staleGracePeriod", monitor.DefaultStaleGracePeriod, "Time in seconds a cell can be missing in R-NIB before it is removed")
+	measurementMaxAge := flag.Int("measurementMaxAge", controller.DefaultMeasurementMaxAge, "Age in seconds after which a measurement is treated as unknown; 0 means no limit")
+	measurementTTL := flag.Int("measurementTTL", monitor.DefaultMeasurementTTL, "Time in seconds after which a measurement is evicted; 0 means no eviction")
+	loadSource := flag.String("loadSource", "current", "Number of UEs the controller decides on: current or forecast")
+	forecastHorizon := flag.Int("forecastHorizon", controller.DefaultForecastHorizon, "Number of cycles ahead to forecast the number of UEs")
+	forecastAlpha := flag.Int("forecastAlpha", controller.DefaultForecastAlpha, "Smoothing factor in percent of the level in the forecast")
+	forecastBeta := flag.Int("forecastBeta", controller.DefaultForecastBeta, "Smoothing factor in percent of the trend in the forecast")
+	historyRetention := flag.Int("historyRetention", controller.DefaultHistoryRetention, "Time in seconds for which the history of loads, Ocns and decisions is kept")
+	historyMaxPoints := flag.Int("historyMaxPoints", controller.DefaultHistoryMaxPoints, "Number of points kept in each time series of the history")
+	storeDir := flag.String("storeDir", "", "Directory of the files which persist Ocns and parameters across restarts; if empty, they are kept in memory")
+	shadowMode := flag.Bool("shadowMode", false, "Only propose Ocns without sending E2 policies")
+	maxWorkers := flag.Int("maxWorkers", controller.DefaultMaxWorkers, "Maximum number of E2 nodes controlled in parallel")
+	nodeTimeout := flag.Int("nodeTimeout", controller.DefaultNodeTimeout, "Timeout in seconds for an E2 node to acknowledge the Ocn policies of its cells")
+	quarantineThreshold := flag.Int("quarantineThreshold", controller.DefaultQuarantineThreshold, "Number of consecutive failed cycles before a cell is quarantined; 0 disables quarantine")
+	quarantineBackoff := flag.Int("quarantineBackoff", controller.DefaultQuarantineBackoff, "Time in seconds for which a quarantined cell is not controlled")
+	algorithm := flag.String("algorithm", "", "Load balancing algorithm; if empty, it is read from config.json")
 
 	flag.Parse()
 
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
 	_, err := certs.HandleCertPaths(*caPath, *keyPath, *certPath, true)
 	if err != nil {
 		log.Fatal(err)
@@ -46,6 +88,42 @@ func main() {
 		RicActionID:         int32(*ricActionID),
 		OverloadThreshold:   *overloadThreshold,
 		TargetLoadThreshold: *targetLoadThreshold,
+		TargetHysteresis:    *targetHysteresis,
+		OverloadHysteresis:  *overloadHysteresis,
+		TimeToTrigger:       *timeToTrigger,
+		PingPongWindow:      *pingPongWindow,
+		PingPongCount:       *pingPongCount,
+		PingPongCooldown:    *pingPongCooldown,
+		PIDKp:               *pidKp,
+		PIDKi:               *pidKi,
+		PIDKd:               *pidKd,
+		PIDIntegralLimit:    *pidIntegralLimit,
+		PairMode:            *pairMode,
+		PairSumBound:        *pairSumBound,
+		GlobalMigrationRate: *globalMigrationRate,
+		ShadowMode:          *shadowMode,
+		LoadMetric:          *loadMetric,
+		DefaultMaxUEs:       *defaultMaxUEs,
+		MaxUEsPer100PRBs:    *maxUEsPer100PRBs,
+		LoadWeightUEs:       *loadWeightUEs,
+		LoadWeightPRBDL:     *loadWeightPRBDL,
+		LoadWeightPRBUL:     *loadWeightPRBUL,
+		StaleGracePeriod:    *staleGracePeriod,
+		MeasurementMaxAge:   *measurementMaxAge,
+		MeasurementTTL:      *measurementTTL,
+		HistoryRetention:    *historyRetention,
+		HistoryMaxPoints:    *historyMaxPoints,
+		LoadSource:          *loadSource,
+		ForecastHorizon:     *forecastHorizon,
+		ForecastAlpha:       *forecastAlpha,
+		ForecastBeta:        *forecastBeta,
+		StoreDir:            *storeDir,
+		Algorithm:           *algorithm,
+		MaxWorkers:          *maxWorkers,
+		NodeTimeout:         *nodeTimeout,
+		QuarantineThreshold: *quarantineThreshold,
+		QuarantineBackoff:   *quarantineBackoff,
+		SetFlags:            setFlags,
 	}
 
 	done := make(chan bool)
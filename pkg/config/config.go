@@ -5,6 +5,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -18,6 +19,8 @@ var log = logging.GetLogger()
 // Config is an interface including app config
 type Config interface {
 	GetInterval(path string) (int, error)
+	GetAlgorithm(path string) (string, error)
+	GetObject(path string, v interface{}) error
 }
 
 // AppConfig is a struct including app config
@@ -49,3 +52,34 @@ func (c *AppConfig) GetInterval(path string) (int, error) {
 
 	return val, nil
 }
+
+// GetAlgorithm gets the name of the load balancing algorithm
+func (c *AppConfig) GetAlgorithm(path string) (string, error) {
+	algorithm, err := c.appConfig.Get(path)
+	if err != nil {
+		return "", err
+	}
+	val, ok := algorithm.Value.(string)
+	if !ok || val == "" {
+		return "", fmt.Errorf("algorithm is not set in %s", path)
+	}
+
+	return val, nil
+}
+
+// GetObject gets a JSON object and decodes it into v
+func (c *AppConfig) GetObject(path string, v interface{}) error {
+	object, err := c.appConfig.Get(path)
+	if err != nil {
+		return err
+	}
+	if object.Value == nil {
+		return fmt.Errorf("%s is not set", path)
+	}
+	bytes, err := json.Marshal(object.Value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, v)
+}
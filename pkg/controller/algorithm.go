@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	thresholdstorage "github.com/onosproject/onos-mlb/pkg/store/threshold"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// DefaultAlgorithm is the name of the algorithm used when no algorithm is configured
+	DefaultAlgorithm = ThresholdAlgorithmName
+)
+
+// Algorithm is an interface of the load balancing algorithm which decides Ocn values
+type Algorithm interface {
+	// Name returns the name of this algorithm
+	Name() string
+
+	// Compute computes the desired Ocn values from the given snapshot
+	Compute(ctx context.Context, snapshot *Snapshot) (*Decision, error)
+}
+
+// NewAlgorithmFunc is a function to generate an algorithm
+type NewAlgorithmFunc func() Algorithm
+
+// Parameters is the set of control parameters an algorithm uses
+type Parameters struct {
+	TargetThreshold     int
+	OverloadThreshold   int
+	DeltaOcn            int
+	TargetHysteresis    int
+	OverloadHysteresis  int
+	TimeToTrigger       int
+	PIDKp               float64
+	PIDKi               float64
+	PIDKd               float64
+	PIDIntegralLimit    float64
+	PairMode            int
+	PairSumBound        int
+	GlobalMigrationRate int
+	LoadMetric          int
+	DefaultMaxUEs       int
+	MaxUEsPer100PRBs    int
+	LoadWeightUEs       int
+	LoadWeightPRBDL     int
+	LoadWeightPRBUL     int
+	MeasurementMaxAge   int
+	LoadSource          int
+	ForecastHorizon     int
+	ForecastAlpha       int
+	ForecastBeta        int
+}
+
+// Snapshot is the network state which an algorithm makes decisions on
+type Snapshot struct {
+	// Cells is the list of cells controlled by this app
+	Cells []storage.IDs
+
+	// NumUEs is the number of UEs in each cell
+	NumUEs map[storage.IDs]int
+
+	// TotalNumUEs is the total number of UEs in all cells
+	TotalNumUEs int
+
+	// Neighbors is the neighbor list of each cell
+	Neighbors map[storage.IDs][]storage.IDs
+
+	// Ocns is the current Ocn map of each cell
+	Ocns map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+
+	// Params is the set of control parameters
+	Params Parameters
+
+	// Capacities is the maximum number of UEs of each cell whose capacity is known in the capacity and composite load metrics
+	Capacities map[storage.IDs]int
+
+	// PRBUsedDL is the downlink PRB usage of each cell in percent in the composite load metric
+	PRBUsedDL map[storage.IDs]int
+
+	// PRBUsedUL is the uplink PRB usage of each cell in percent in the composite load metric
+	PRBUsedUL map[storage.IDs]int
+
+	// Thresholds is the effective thresholds of the cells and the neighbor cells which have threshold overrides
+	Thresholds map[storage.IDs]thresholdstorage.Profile
+
+	// Overloaded is true for the cell whose overload condition is triggered
+	Overloaded map[storage.IDs]bool
+
+	// Underloaded is true for the cell whose underload condition (i.e., load below target) is triggered
+	Underloaded map[storage.IDs]bool
+
+	// Held is true for the cell whose Ocns are all kept by an exclusion, quarantine or ping-pong freeze,
+	// so that whatever an algorithm decides for it is not applied in this cycle
+	Held map[storage.IDs]bool
+}
+
+// FindCell finds the cell in this snapshot with PLMN ID and cell ID
+func (s *Snapshot) FindCell(plmnID string, cellID string) (storage.IDs, error) {
+	for _, cell := range s.Cells {
+		if cell.PlmnID == plmnID && cell.CellID == cellID {
+			return cell, nil
+		}
+	}
+	return storage.IDs{}, errors.NewNotFound("ID not found with plmnid and cgi")
+}
+
+// Load returns the load of the cell in percent;
+// in the capacity and composite load metrics, it may exceed 100 if the cell has more UEs than its capacity
+func (s *Snapshot) Load(ids storage.IDs) (int, error) {
+	cell, err := s.FindCell(ids.PlmnID, ids.CellID)
+	if err != nil {
+		return 0, err
+	}
+	numUEs, ok := s.NumUEs[cell]
+	if !ok {
+		return 0, errors.NewNotFound("num(UEs) not found")
+	}
+	ueLoad := 100 - getCapacity(1, s.TotalNumUEs, numUEs)
+	if maxUEs, ok := s.Capacities[cell]; ok && s.Params.LoadMetric != LoadMetricShare {
+		ueLoad = numUEs * 100 / maxUEs
+	}
+	if s.Params.LoadMetric == LoadMetricComposite {
+		return s.compositeLoad(cell, ueLoad), nil
+	}
+	return ueLoad, nil
+}
+
+// Capacity returns the number of UEs which makes the load of the cell 100 percent
+func (s *Snapshot) Capacity(ids storage.IDs) int {
+	if cell, err := s.FindCell(ids.PlmnID, ids.CellID); err == nil {
+		if maxUEs, ok := s.Capacities[cell]; ok && s.Params.LoadMetric != LoadMetricShare {
+			return maxUEs
+		}
+	}
+	if s.TotalNumUEs == 0 {
+		// any capacity gives zero load without UEs
+		return 1
+	}
+	return s.TotalNumUEs
+}
+
+// IsOverloaded returns true if the overload condition of the cell is triggered;
+// for the cell without trigger state, it compares the load with the overload threshold
+func (s *Snapshot) IsOverloaded(ids storage.IDs) bool {
+	if cell, err := s.FindCell(ids.PlmnID, ids.CellID); err == nil {
+		if overloaded, ok := s.Overloaded[cell]; ok {
+			return overloaded
+		}
+	}
+	return s.loadOrZero(ids) > s.OverloadThreshold(ids)
+}
+
+// IsUnderloaded returns true if the underload condition of the cell is triggered;
+// for the cell without trigger state, it compares the load with the target threshold
+func (s *Snapshot) IsUnderloaded(ids storage.IDs) bool {
+	if cell, err := s.FindCell(ids.PlmnID, ids.CellID); err == nil {
+		if underloaded, ok := s.Underloaded[cell]; ok {
+			return underloaded
+		}
+	}
+	return s.loadOrZero(ids) < s.TargetThreshold(ids)
+}
+
+// TargetThreshold returns the effective target threshold of the cell
+func (s *Snapshot) TargetThreshold(ids storage.IDs) int {
+	return s.thresholds(ids).TargetThreshold
+}
+
+// OverloadThreshold returns the effective overload threshold of the cell
+func (s *Snapshot) OverloadThreshold(ids storage.IDs) int {
+	return s.thresholds(ids).OverloadThreshold
+}
+
+func (s *Snapshot) thresholds(ids storage.IDs) thresholdstorage.Profile {
+	if p, ok := s.Thresholds[ids]; ok {
+		return p
+	}
+	if cell, err := s.FindCell(ids.PlmnID, ids.CellID); err == nil {
+		if p, ok := s.Thresholds[cell]; ok {
+			return p
+		}
+	}
+	return thresholdstorage.Profile{
+		TargetThreshold:   s.Params.TargetThreshold,
+		OverloadThreshold: s.Params.OverloadThreshold,
+	}
+}
+
+func (s *Snapshot) loadOrZero(ids storage.IDs) int {
+	load, err := s.Load(ids)
+	if err != nil {
+		log.Warnf("there is no num(UEs) measurement value; this neighbor (plmnid-%v:cid-%v) may not be controlled by this xAPP; set num(UEs) to 0", ids.PlmnID, ids.CellID)
+		return 100 - getCapacity(1, s.TotalNumUEs, 0)
+	}
+	return load
+}
+
+// Ocn returns the current Ocn from the serving cell to the neighbor cell
+func (s *Snapshot) Ocn(ids storage.IDs, nIDs storage.IDs) (meastype.QOffsetRange, error) {
+	if ocn, ok := s.Ocns[ids][nIDs]; ok {
+		return ocn, nil
+	}
+	return 0, errors.NewNotFound("element does not exist")
+}
+
+// Decision is the result of an algorithm
+type Decision struct {
+	// Ocns is the Ocn map to be applied to each serving cell
+	Ocns map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+
+	// Errors has the reason why the algorithm could not decide for a serving cell
+	Errors map[storage.IDs]error
+}
+
+// NewDecision generates an empty decision
+func NewDecision() *Decision {
+	return &Decision{
+		Ocns:   make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+		Errors: make(map[storage.IDs]error),
+	}
+}
+
+var (
+	algorithms  = make(map[string]NewAlgorithmFunc)
+	algorithmMu sync.RWMutex
+)
+
+// RegisterAlgorithm registers an algorithm with its name so that it can be selected by configuration
+func RegisterAlgorithm(name string, newFunc NewAlgorithmFunc) {
+	algorithmMu.Lock()
+	defer algorithmMu.Unlock()
+	algorithms[name] = newFunc
+}
+
+// NewAlgorithm generates the algorithm registered with the name
+func NewAlgorithm(name string) (Algorithm, error) {
+	algorithmMu.RLock()
+	defer algorithmMu.RUnlock()
+	newFunc, ok := algorithms[name]
+	if !ok {
+		return nil, errors.NewNotFound("algorithm %s is not registered - available algorithms: %v", name, listAlgorithms())
+	}
+	return newFunc(), nil
+}
+
+func listAlgorithms() []string {
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getCapacity(denominationFactor float64, totalNumUEs int, numUEs int) int {
+	capacity := (1 - float64(numUEs)/(denominationFactor*float64(totalNumUEs))) * 100
+	return int(capacity)
+}
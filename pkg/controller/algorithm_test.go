@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPlmnID = "138426"
+
+func testCell(cellID string) storage.IDs {
+	return storage.IDs{
+		NodeID:    "e2:1/" + cellID,
+		PlmnID:    testPlmnID,
+		CellID:    cellID,
+		CellObjID: cellID,
+	}
+}
+
+// testNeighbor is the neighbor IDs of a cell, which do not have E2 node ID
+func testNeighbor(cellID string) storage.IDs {
+	return storage.IDs{
+		PlmnID: testPlmnID,
+		CellID: cellID,
+	}
+}
+
+// newTestSnapshot generates the snapshot whose cells are all neighbors of each other with the same Ocn;
+// a cell without num(UEs) is controlled but its load is unknown
+func newTestSnapshot(numUEs map[string]int, noNumUEs []string, ocn meastype.QOffsetRange, params Parameters) *Snapshot {
+	cellIDs := make([]string, 0, len(numUEs)+len(noNumUEs))
+	for cellID := range numUEs {
+		cellIDs = append(cellIDs, cellID)
+	}
+	cellIDs = append(cellIDs, noNumUEs...)
+	sort.Strings(cellIDs)
+
+	snapshot := &Snapshot{
+		NumUEs:    make(map[storage.IDs]int),
+		Neighbors: make(map[storage.IDs][]storage.IDs),
+		Ocns:      make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+		Params:    params,
+	}
+	for _, cellID := range cellIDs {
+		cell := testCell(cellID)
+		snapshot.Cells = append(snapshot.Cells, cell)
+		if n, ok := numUEs[cellID]; ok {
+			snapshot.NumUEs[cell] = n
+			snapshot.TotalNumUEs += n
+		}
+		snapshot.Ocns[cell] = make(map[storage.IDs]meastype.QOffsetRange)
+		for _, nCellID := range cellIDs {
+			if nCellID == cellID {
+				continue
+			}
+			snapshot.Neighbors[cell] = append(snapshot.Neighbors[cell], testNeighbor(nCellID))
+			snapshot.Ocns[cell][testNeighbor(nCellID)] = ocn
+		}
+	}
+	return snapshot
+}
+
+func TestNewAlgorithm(t *testing.T) {
+	for _, name := range []string{ThresholdAlgorithmName, PIDAlgorithmName, GlobalAlgorithmName} {
+		algorithm, err := NewAlgorithm(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, algorithm.Name())
+	}
+
+	_, err := NewAlgorithm("unknown")
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	RegisterAlgorithm("test", NewThresholdAlgorithm)
+	defer func() {
+		algorithmMu.Lock()
+		delete(algorithms, "test")
+		algorithmMu.Unlock()
+	}()
+
+	algorithm, err := NewAlgorithm("test")
+	assert.NoError(t, err)
+	assert.Equal(t, ThresholdAlgorithmName, algorithm.Name())
+}
+
+func TestThresholdAlgorithm(t *testing.T) {
+	tests := []struct {
+		name     string
+		numUEs   map[string]int
+		noNumUEs []string
+		ocn      meastype.QOffsetRange
+		expected map[string]map[string]meastype.QOffsetRange
+		errors   []string
+	}{
+		{
+			name:   "overloaded cell offloads to underloaded neighbor",
+			numUEs: map[string]int{"a": 80, "b": 20},
+			ocn:    meastype.QOffset0dB,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": meastype.QOffset3dB},
+				"b": {"a": meastype.QOffsetMinus3dB},
+			},
+		},
+		{
+			name:     "loads between thresholds",
+			numUEs:   map[string]int{"a": 55, "b": 45},
+			ocn:      meastype.QOffset0dB,
+			expected: map[string]map[string]meastype.QOffsetRange{},
+		},
+		{
+			name:   "Ocn is clamped into its range",
+			numUEs: map[string]int{"a": 80, "b": 20},
+			ocn:    meastype.QOffset24dB - 1,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": meastype.QOffset24dB},
+				"b": {"a": meastype.QOffset24dB - 4},
+			},
+		},
+		{
+			name:     "neighbor without num(UEs) has no load",
+			numUEs:   map[string]int{"a": 50},
+			noNumUEs: []string{"b"},
+			ocn:      meastype.QOffset0dB,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": meastype.QOffset3dB},
+			},
+			errors: []string{"b"},
+		},
+	}
+
+	params := Parameters{
+		TargetThreshold:   30,
+		OverloadThreshold: 70,
+		DeltaOcn:          3,
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshot := newTestSnapshot(test.numUEs, test.noNumUEs, test.ocn, params)
+			decision, err := NewThresholdAlgorithm().Compute(context.Background(), snapshot)
+			assert.NoError(t, err)
+			assertDecision(t, test.expected, decision)
+			assert.Len(t, decision.Errors, len(test.errors))
+			for _, cellID := range test.errors {
+				assert.Contains(t, decision.Errors, testCell(cellID))
+			}
+		})
+	}
+}
+
+// assertDecision checks the decided Ocns of each cell to its neighbors
+func assertDecision(t *testing.T, expected map[string]map[string]meastype.QOffsetRange, decision *Decision) {
+	assert.Len(t, decision.Ocns, len(expected))
+	for cellID, ocns := range expected {
+		assert.Contains(t, decision.Ocns, testCell(cellID))
+		for nCellID, ocn := range ocns {
+			assert.Equal(t, ocn, decision.Ocns[testCell(cellID)][testNeighbor(nCellID)], "Ocn from %s to %s", cellID, nCellID)
+		}
+	}
+}
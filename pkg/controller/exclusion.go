@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+)
+
+// applyExclusions removes the excluded cells from the decision and keeps the current Ocn of the excluded relations
+func (h *handler) applyExclusions(ctx context.Context, snapshot *Snapshot, decision *Decision) {
+	report := exclusionstorage.Report{}
+	for _, ids := range snapshot.Cells {
+		if h.exclusionStore.IsCellExcluded(ctx, ids) {
+			report.Cells = append(report.Cells, ids)
+			if _, ok := decision.Ocns[ids]; ok {
+				log.Debugf("Cell %v is excluded - keep its Ocns", ids)
+				delete(decision.Ocns, ids)
+			}
+			continue
+		}
+		for _, nIDs := range snapshot.Neighbors[ids] {
+			// neighbor IDs do not have E2 node ID; use the cell's IDs if this app controls it
+			nCell, err := snapshot.FindCell(nIDs.PlmnID, nIDs.CellID)
+			if err != nil {
+				nCell = nIDs
+			}
+			if !h.exclusionStore.IsRelationExcluded(ctx, ids, nCell) {
+				continue
+			}
+			report.Relations = append(report.Relations, exclusionstorage.RelationIDs{
+				Cell:     ids,
+				Neighbor: nIDs,
+			})
+			ocns, ok := decision.Ocns[ids]
+			if !ok {
+				continue
+			}
+			ocn, err := snapshot.Ocn(ids, nIDs)
+			if err != nil {
+				delete(ocns, nIDs)
+				continue
+			}
+			log.Debugf("Relation from %v to %v is excluded - keep Ocn %v", ids, nIDs, ocn)
+			ocns[nIDs] = ocn
+		}
+	}
+	err := h.exclusionStore.PutReport(ctx, report)
+	if err != nil {
+		log.Error(err)
+	}
+}
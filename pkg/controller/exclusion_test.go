@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyExclusions(t *testing.T) {
+	tests := []struct {
+		name  string
+		lists exclusionstorage.Lists
+		// missing is the relation whose Ocn is not in the snapshot
+		missing [2]string
+		// expected Ocns are the decided ones unless the snapshot ones are kept
+		expected  map[string]map[string]meastype.QOffsetRange
+		cells     []string
+		relations [][2]string
+	}{
+		{
+			name: "no lists",
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(2), "c": ocnStep(2)},
+				"b": {"a": ocnStep(2), "c": ocnStep(2)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+		},
+		{
+			name:  "denied cell keeps all its Ocns",
+			lists: exclusionstorage.Lists{DenyCells: []exclusionstorage.Rule{{CellID: "a"}}},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"b": {"a": ocnStep(2), "c": ocnStep(2)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			cells: []string{"a"},
+		},
+		{
+			name:  "allowed cell is the only one controlled",
+			lists: exclusionstorage.Lists{AllowCells: []exclusionstorage.Rule{{CellID: "c"}}},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			cells: []string{"a", "b"},
+		},
+		{
+			name: "deny wins over allow",
+			lists: exclusionstorage.Lists{
+				AllowCells: []exclusionstorage.Rule{{PlmnID: testPlmnID}},
+				DenyCells:  []exclusionstorage.Rule{{CellID: "b"}},
+			},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(2), "c": ocnStep(2)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			cells: []string{"b"},
+		},
+		{
+			name: "denied relation keeps its Ocn only",
+			lists: exclusionstorage.Lists{DenyRelations: []exclusionstorage.Relation{
+				{Cell: exclusionstorage.Rule{CellID: "a"}, Neighbor: exclusionstorage.Rule{CellID: "b"}},
+			}},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(0), "c": ocnStep(2)},
+				"b": {"a": ocnStep(2), "c": ocnStep(2)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			relations: [][2]string{{"a", "b"}},
+		},
+		{
+			name: "relation matched by the E2 node ID of the neighbor",
+			lists: exclusionstorage.Lists{DenyRelations: []exclusionstorage.Relation{
+				{Neighbor: exclusionstorage.Rule{NodeID: testCell("c").NodeID}},
+			}},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(2), "c": ocnStep(0)},
+				"b": {"a": ocnStep(2), "c": ocnStep(0)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			relations: [][2]string{{"a", "c"}, {"b", "c"}},
+		},
+		{
+			name: "allowed relation is the only one controlled",
+			lists: exclusionstorage.Lists{AllowRelations: []exclusionstorage.Relation{
+				{Cell: exclusionstorage.Rule{CellID: "b"}, Neighbor: exclusionstorage.Rule{CellID: "a"}},
+			}},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(0), "c": ocnStep(0)},
+				"b": {"a": ocnStep(2), "c": ocnStep(0)},
+				"c": {"a": ocnStep(0), "b": ocnStep(0)},
+			},
+			relations: [][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}, {"c", "a"}, {"c", "b"}},
+		},
+		{
+			name: "denied cell takes precedence over its relations",
+			lists: exclusionstorage.Lists{
+				DenyCells: []exclusionstorage.Rule{{CellID: "a"}},
+				DenyRelations: []exclusionstorage.Relation{
+					{Cell: exclusionstorage.Rule{CellID: "a"}, Neighbor: exclusionstorage.Rule{CellID: "b"}},
+				},
+			},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"b": {"a": ocnStep(2), "c": ocnStep(2)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			cells: []string{"a"},
+		},
+		{
+			name: "denied relation without the current Ocn is not changed",
+			lists: exclusionstorage.Lists{DenyRelations: []exclusionstorage.Relation{
+				{Cell: exclusionstorage.Rule{CellID: "a"}, Neighbor: exclusionstorage.Rule{CellID: "b"}},
+			}},
+			missing: [2]string{"a", "b"},
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"c": ocnStep(2)},
+				"b": {"a": ocnStep(2), "c": ocnStep(2)},
+				"c": {"a": ocnStep(2), "b": ocnStep(2)},
+			},
+			relations: [][2]string{{"a", "b"}},
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &handler{exclusionStore: exclusionstorage.NewStore()}
+			assert.NoError(t, h.exclusionStore.SetLists(ctx, test.lists))
+
+			snapshot := newTestSnapshot(map[string]int{"a": 10, "b": 10, "c": 10}, nil, ocnStep(0), Parameters{})
+			decision := &Decision{
+				Ocns:   make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+				Errors: make(map[storage.IDs]error),
+			}
+			for _, ids := range snapshot.Cells {
+				decision.Ocns[ids] = make(map[storage.IDs]meastype.QOffsetRange)
+				for _, nIDs := range snapshot.Neighbors[ids] {
+					decision.Ocns[ids][nIDs] = ocnStep(2)
+				}
+			}
+			if test.missing[0] != "" {
+				delete(snapshot.Ocns[testCell(test.missing[0])], testNeighbor(test.missing[1]))
+			}
+
+			h.applyExclusions(ctx, snapshot, decision)
+			assertDecision(t, test.expected, decision)
+			for cellID, ocns := range test.expected {
+				assert.Len(t, decision.Ocns[testCell(cellID)], len(ocns), "Ocns of %s", cellID)
+			}
+
+			report, err := h.exclusionStore.GetReport(ctx)
+			assert.NoError(t, err)
+			cells := make([]storage.IDs, 0)
+			for _, cellID := range test.cells {
+				cells = append(cells, testCell(cellID))
+			}
+			assert.ElementsMatch(t, cells, report.Cells)
+			relations := make([]exclusionstorage.RelationIDs, 0)
+			for _, r := range test.relations {
+				relations = append(relations, exclusionstorage.RelationIDs{Cell: testCell(r[0]), Neighbor: testNeighbor(r[1])})
+			}
+			assert.ElementsMatch(t, relations, report.Relations)
+		})
+	}
+}
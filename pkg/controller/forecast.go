@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"math"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/estimator"
+)
+
+const (
+	// LoadSourceCurrent makes the controller decide on the current number of UEs
+	LoadSourceCurrent = iota
+
+	// LoadSourceForecast makes the controller decide on the number of UEs forecast some cycles ahead
+	LoadSourceForecast
+)
+
+const (
+	// DefaultForecastHorizon is the default number of cycles ahead to forecast the number of UEs
+	DefaultForecastHorizon = 1
+
+	// DefaultForecastAlpha is the default smoothing factor in percent of the level in the forecast
+	DefaultForecastAlpha = 50
+
+	// DefaultForecastBeta is the default smoothing factor in percent of the trend in the forecast
+	DefaultForecastBeta = 30
+)
+
+var loadSources = map[string]int{
+	"":         LoadSourceCurrent,
+	"current":  LoadSourceCurrent,
+	"forecast": LoadSourceForecast,
+}
+
+// ParseLoadSource parses the name of the load source
+func ParseLoadSource(name string) (int, error) {
+	if source, ok := loadSources[name]; ok {
+		return source, nil
+	}
+	return LoadSourceCurrent, errors.NewInvalid("unknown load source %s", name)
+}
+
+// forecastLoads forecasts the number of UEs of each cell to track the forecast errors;
+// with the forecast load source, the snapshot has the forecast number of UEs instead of the current one
+func (h *handler) forecastLoads(ctx context.Context, snapshot *Snapshot) {
+	if h.forecaster == nil {
+		return
+	}
+	h.forecaster.Retain(ctx, snapshot.Cells)
+	params := estimator.ForecastParams{
+		Alpha:   float64(snapshot.Params.ForecastAlpha) / 100,
+		Beta:    float64(snapshot.Params.ForecastBeta) / 100,
+		Horizon: snapshot.Params.ForecastHorizon,
+	}
+	forecasts := make(map[string]int)
+	for _, cell := range snapshot.Cells {
+		numUEs, ok := snapshot.NumUEs[cell]
+		if !ok {
+			continue
+		}
+		forecast := h.forecaster.Forecast(ctx, cell, float64(numUEs), params)
+		if snapshot.Params.LoadSource != LoadSourceForecast {
+			continue
+		}
+		predicted := int(math.Max(0, math.Round(forecast)))
+		snapshot.TotalNumUEs += predicted - numUEs
+		snapshot.NumUEs[cell] = predicted
+		forecasts[cell.CellID] = predicted
+	}
+	if len(forecasts) > 0 {
+		log.Debugf("Forecast num(UEs) %v cycles ahead: %v", params.Horizon, forecasts)
+	}
+}
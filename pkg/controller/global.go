@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// GlobalAlgorithmName is the name of the algorithm which solves Ocns of all cells at once
+	GlobalAlgorithmName = "global"
+
+	// DefaultGlobalMigrationRate is the default share of UEs in percent moved by an Ocn step
+	DefaultGlobalMigrationRate = 5
+
+	// globalMaxIterations limits the number of Ocn steps the solver takes in a cycle
+	globalMaxIterations = 1000
+)
+
+func init() {
+	RegisterAlgorithm(GlobalAlgorithmName, NewGlobalAlgorithm)
+}
+
+// NewGlobalAlgorithm generates the algorithm which computes Ocns of all cells in one pass
+// to minimize the variance of the load across cells.
+//
+// It assumes that increasing Ocn from cell A to neighbor B by a step moves the migration rate of A's UEs to B,
+// and decreasing it by a step moves them back. Starting from the current Ocns, it greedily takes the Ocn step
+// which reduces the variance the most until no step reduces it. Each Ocn moves at most delta_ocn steps per cycle
+// and stays in the range from QOffsetMinus24dB to QOffset24dB.
+func NewGlobalAlgorithm() Algorithm {
+	return &globalAlgorithm{}
+}
+
+type globalAlgorithm struct{}
+
+type globalMove struct {
+	pair ocnPair
+	b    storage.IDs
+	step int
+}
+
+func (a *globalAlgorithm) Name() string {
+	return GlobalAlgorithmName
+}
+
+func (a *globalAlgorithm) Compute(_ context.Context, snapshot *Snapshot) (*Decision, error) {
+	decision := NewDecision()
+	params := snapshot.Params
+	rate := float64(params.GlobalMigrationRate) / 100
+
+	cells := make([]storage.IDs, len(snapshot.Cells))
+	copy(cells, snapshot.Cells)
+	sort.Slice(cells, func(i, j int) bool {
+		return idsLess(cells[i], cells[j])
+	})
+
+	// loads are in UEs per capacity so that moving UEs changes the load of each cell according to its capacity
+	loads := make(map[storage.IDs]float64)
+	sum := 0.0
+	for _, cell := range cells {
+		loads[cell] = float64(snapshot.NumUEs[cell]) / float64(snapshot.Capacity(cell))
+		sum += loads[cell]
+	}
+
+	// candidate relations between cells controlled by this app;
+	// a cell missing the Ocn of any of them is skipped as a whole, so that none of its Ocns changes in a failed cycle
+	pairs := make([]globalMove, 0)
+	for _, cell := range cells {
+		cellPairs := make([]globalMove, 0, len(snapshot.Neighbors[cell]))
+		var cellErr error
+		for _, nIDs := range snapshot.Neighbors[cell] {
+			b, err := snapshot.FindCell(nIDs.PlmnID, nIDs.CellID)
+			if err != nil {
+				continue
+			}
+			if _, err := snapshot.Ocn(cell, nIDs); err != nil {
+				cellErr = err
+				break
+			}
+			cellPairs = append(cellPairs, globalMove{
+				pair: ocnPair{sCell: cell, nCell: nIDs},
+				b:    b,
+			})
+		}
+		if cellErr != nil {
+			decision.Errors[cell] = cellErr
+			continue
+		}
+		pairs = append(pairs, cellPairs...)
+	}
+
+	steps := make(map[ocnPair]int)
+	for i := 0; i < globalMaxIterations; i++ {
+		var best globalMove
+		bestGain := 0.0
+		for _, p := range pairs {
+			for _, step := range []int{1, -1} {
+				total := steps[p.pair] + step
+				if total > params.DeltaOcn || total < -params.DeltaOcn {
+					continue
+				}
+				ocn, _ := snapshot.Ocn(p.pair.sCell, p.pair.nCell)
+				if int(ocn)+total < int(meastype.QOffsetMinus24dB) || int(ocn)+total > int(meastype.QOffset24dB) {
+					continue
+				}
+				moved := float64(step) * rate * float64(snapshot.NumUEs[p.pair.sCell])
+				lA := loads[p.pair.sCell]
+				lB := loads[p.b]
+				dA := moved / float64(snapshot.Capacity(p.pair.sCell))
+				dB := moved / float64(snapshot.Capacity(p.b))
+				// N * variance is the sum of squares minus the squared sum over N;
+				// the sum does not change unless the capacities of the two cells differ
+				gain := lA*lA + lB*lB - (lA-dA)*(lA-dA) - (lB+dB)*(lB+dB)
+				gain -= (sum*sum - (sum-dA+dB)*(sum-dA+dB)) / float64(len(cells))
+				if gain > bestGain {
+					bestGain = gain
+					best = globalMove{
+						pair: p.pair,
+						b:    p.b,
+						step: step,
+					}
+				}
+			}
+		}
+		if bestGain <= 0 {
+			break
+		}
+		moved := float64(best.step) * rate * float64(snapshot.NumUEs[best.pair.sCell])
+		dA := moved / float64(snapshot.Capacity(best.pair.sCell))
+		dB := moved / float64(snapshot.Capacity(best.b))
+		loads[best.pair.sCell] -= dA
+		loads[best.b] += dB
+		sum += dB - dA
+		steps[best.pair] += best.step
+	}
+
+	for pair, step := range steps {
+		if step == 0 {
+			continue
+		}
+		if _, ok := decision.Ocns[pair.sCell]; !ok {
+			ocns := make(map[storage.IDs]meastype.QOffsetRange)
+			for k, v := range snapshot.Ocns[pair.sCell] {
+				ocns[k] = v
+			}
+			decision.Ocns[pair.sCell] = ocns
+		}
+		decision.Ocns[pair.sCell][pair.nCell] = clampOcn(int(snapshot.Ocns[pair.sCell][pair.nCell]) + step)
+	}
+	log.Debugf("Global algorithm expects loads %v after applying Ocn steps %v", loads, steps)
+
+	return decision, nil
+}
+
+func idsLess(a storage.IDs, b storage.IDs) bool {
+	if a.NodeID != b.NodeID {
+		return a.NodeID < b.NodeID
+	}
+	if a.PlmnID != b.PlmnID {
+		return a.PlmnID < b.PlmnID
+	}
+	if a.CellID != b.CellID {
+		return a.CellID < b.CellID
+	}
+	return a.CellObjID < b.CellObjID
+}
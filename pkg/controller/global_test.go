@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalAlgorithm(t *testing.T) {
+	tests := []struct {
+		name     string
+		numUEs   map[string]int
+		ocn      meastype.QOffsetRange
+		deltaOcn int
+		expected map[string]map[string]meastype.QOffsetRange
+	}{
+		{
+			name:     "balanced loads",
+			numUEs:   map[string]int{"a": 50, "b": 50},
+			ocn:      meastype.QOffset0dB,
+			deltaOcn: 3,
+			expected: map[string]map[string]meastype.QOffsetRange{},
+		},
+		{
+			name:     "both sides move UEs to the less loaded cell",
+			numUEs:   map[string]int{"a": 80, "b": 20},
+			ocn:      meastype.QOffset0dB,
+			deltaOcn: 3,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(3)},
+				"b": {"a": ocnStep(-3)},
+			},
+		},
+		{
+			name:     "each Ocn moves at most delta_ocn steps",
+			numUEs:   map[string]int{"a": 80, "b": 20},
+			ocn:      meastype.QOffset0dB,
+			deltaOcn: 1,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"a": {"b": ocnStep(1)},
+				"b": {"a": ocnStep(-1)},
+			},
+		},
+		{
+			name:     "Ocn at the end of the range",
+			numUEs:   map[string]int{"a": 80, "b": 20},
+			ocn:      meastype.QOffset24dB,
+			deltaOcn: 3,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				"b": {"a": meastype.QOffset24dB - 3},
+			},
+		},
+		{
+			name:     "steps stop when the loads are balanced",
+			numUEs:   map[string]int{"a": 55, "b": 45},
+			ocn:      meastype.QOffset0dB,
+			deltaOcn: 10,
+			expected: map[string]map[string]meastype.QOffsetRange{
+				// a step of a moves 2.75 UEs and a step of b moves 2.25 UEs
+				"a": {"b": ocnStep(1)},
+				"b": {"a": ocnStep(-1)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := Parameters{
+				DeltaOcn:            test.deltaOcn,
+				GlobalMigrationRate: 5,
+			}
+			snapshot := newTestSnapshot(test.numUEs, nil, test.ocn, params)
+			decision, err := NewGlobalAlgorithm().Compute(context.Background(), snapshot)
+			assert.NoError(t, err)
+			assertDecision(t, test.expected, decision)
+			assert.Empty(t, decision.Errors)
+		})
+	}
+}
+
+func TestGlobalAlgorithmMissingOcn(t *testing.T) {
+	params := Parameters{
+		DeltaOcn:            3,
+		GlobalMigrationRate: 5,
+	}
+	snapshot := newTestSnapshot(map[string]int{"a": 80, "b": 20, "c": 20}, nil, meastype.QOffset0dB, params)
+	delete(snapshot.Ocns[testCell("a")], testNeighbor("c"))
+	decision, err := NewGlobalAlgorithm().Compute(context.Background(), snapshot)
+	assert.NoError(t, err)
+
+	// the cell is skipped as a whole, even the relation whose Ocn is known
+	assert.Len(t, decision.Errors, 1)
+	assert.True(t, errors.IsNotFound(decision.Errors[testCell("a")]))
+	assert.NotContains(t, decision.Ocns, testCell("a"))
+	// the other cells still move UEs from the cell
+	assert.Less(t, decision.Ocns[testCell("b")][testNeighbor("a")], meastype.QOffset0dB)
+	assert.Less(t, decision.Ocns[testCell("c")][testNeighbor("a")], meastype.QOffset0dB)
+}
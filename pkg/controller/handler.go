@@ -8,14 +8,25 @@ package controller
 import (
 	"context"
 	"github.com/onosproject/onos-mlb/pkg/southbound/e2policy"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-mlb/pkg/estimator"
 	"github.com/onosproject/onos-mlb/pkg/monitor"
+	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	capacitystorage "github.com/onosproject/onos-mlb/pkg/store/capacity"
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
 	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
 	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
 	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	thresholdstorage "github.com/onosproject/onos-mlb/pkg/store/threshold"
+	triggerstorage "github.com/onosproject/onos-mlb/pkg/store/trigger"
+	"github.com/onosproject/onos-mlb/pkg/store/txn"
 	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
 )
 
@@ -24,22 +35,74 @@ var log = logging.GetLogger()
 const (
 	// RcPreRanParamDefaultOCN is default Ocn value
 	RcPreRanParamDefaultOCN = meastype.QOffset0dB
+
+	// DefaultMeasurementMaxAge is the default age in seconds after which a measurement is treated as unknown;
+	// 0 means no limit
+	DefaultMeasurementMaxAge = 0
+
+	// DefaultMaxWorkers is the default number of E2 nodes controlled in parallel
+	DefaultMaxWorkers = 8
+
+	// DefaultNodeTimeout is the default timeout in seconds for an E2 node to acknowledge the Ocn policies of its cells
+	DefaultNodeTimeout = 10
+
+	// DefaultQuarantineThreshold is the default number of consecutive failed cycles before a cell is quarantined
+	DefaultQuarantineThreshold = 3
+
+	// DefaultQuarantineBackoff is the default time in seconds for which a quarantined cell is not controlled
+	DefaultQuarantineBackoff = 60
 )
 
+// Stores is the set of stores and estimators the controller reads and writes
+type Stores struct {
+	NumUEs      storage.MeasurementStore
+	Neighbors   storage.NeighborStore
+	NumPRBs     storage.MeasurementStore
+	PRBUsedDL   storage.MeasurementStore
+	PRBUsedUL   storage.MeasurementStore
+	Ocn         ocnstorage.Store
+	ProposedOcn ocnstorage.Store
+	Params      paramstorage.Store
+	Failure     failurestorage.Store
+	// ShadowFailure has the failure records of the cells in shadow mode
+	ShadowFailure failurestorage.Store
+	Trigger       triggerstorage.Store
+	Exclusion     exclusionstorage.Store
+	Threshold     thresholdstorage.Store
+	Capacity      capacitystorage.Store
+	History       historystorage.Store
+	// Group coordinates the reads and writes of the measurement and Ocn stores with the monitor
+	Group         *txn.Group
+	LoadEstimator estimator.Estimator
+	Forecaster    estimator.Forecaster
+}
+
 // NewHandler generates new MLB controller handler
-func NewHandler(e2policyHandler e2policy.Handler,
-	monitorHandler monitor.Handler,
-	numUEsMeasStore storage.Store,
-	neighborMeasStore storage.Store,
-	ocnStore ocnstorage.Store,
-	paramStore paramstorage.Store) Handler {
+func NewHandler(e2policyHandler e2policy.Handler, monitorHandler monitor.Handler, stores Stores, algorithm Algorithm) Handler {
 	return &handler{
-		e2PolicyHandler:   e2policyHandler,
-		monitorHandler:    monitorHandler,
-		numUEsMeasStore:   numUEsMeasStore,
-		neighborMeasStore: neighborMeasStore,
-		ocnStore:          ocnStore,
-		paramStore:        paramStore,
+		algorithm:          algorithm,
+		e2PolicyHandler:    e2policyHandler,
+		monitorHandler:     monitorHandler,
+		numUEsMeasStore:    stores.NumUEs,
+		neighborMeasStore:  stores.Neighbors,
+		ocnStore:           stores.Ocn,
+		proposedOcnStore:   stores.ProposedOcn,
+		paramStore:         stores.Params,
+		failureStore:       stores.Failure,
+		shadowFailureStore: stores.ShadowFailure,
+		triggerStore:       stores.Trigger,
+		exclusionStore:     stores.Exclusion,
+		thresholdStore:     stores.Threshold,
+		numPRBsMeasStore:   stores.NumPRBs,
+		capacityStore:      stores.Capacity,
+		prbUsedDLMeasStore: stores.PRBUsedDL,
+		prbUsedULMeasStore: stores.PRBUsedUL,
+		historyStore:       stores.History,
+		loadEstimator:      stores.LoadEstimator,
+		forecaster:         stores.Forecaster,
+		storeGroup:         stores.Group,
+		pingPong:           newPingPongDetector(),
+		shadowPingPong:     newPingPongDetector(),
 	}
 }
 
@@ -50,12 +113,30 @@ type Handler interface {
 }
 
 type handler struct {
-	e2PolicyHandler   e2policy.Handler
-	monitorHandler    monitor.Handler
-	numUEsMeasStore   storage.Store
-	neighborMeasStore storage.Store
-	ocnStore          ocnstorage.Store
-	paramStore        paramstorage.Store
+	algorithm          Algorithm
+	e2PolicyHandler    e2policy.Handler
+	monitorHandler     monitor.Handler
+	numUEsMeasStore    storage.MeasurementStore
+	neighborMeasStore  storage.NeighborStore
+	ocnStore           ocnstorage.Store
+	proposedOcnStore   ocnstorage.Store
+	paramStore         paramstorage.Store
+	failureStore       failurestorage.Store
+	shadowFailureStore failurestorage.Store
+	triggerStore       triggerstorage.Store
+	exclusionStore     exclusionstorage.Store
+	thresholdStore     thresholdstorage.Store
+	numPRBsMeasStore   storage.MeasurementStore
+	capacityStore      capacitystorage.Store
+	prbUsedDLMeasStore storage.MeasurementStore
+	prbUsedULMeasStore storage.MeasurementStore
+	historyStore       historystorage.Store
+	loadEstimator      estimator.Estimator
+	forecaster         estimator.Forecaster
+	storeGroup         *txn.Group
+	pingPong           *pingPongDetector
+	shadowPingPong     *pingPongDetector
+	running            atomic.Bool
 }
 
 func (h *handler) Run(ctx context.Context) error {
@@ -67,8 +148,15 @@ func (h *handler) Run(ctx context.Context) error {
 		}
 		select {
 		case <-time.After(time.Duration(interval) * time.Second):
-			// ToDo should run as goroutine
-			h.startControlLogic(ctx)
+			// a control cycle must not overlap the next one
+			if !h.running.CompareAndSwap(false, true) {
+				log.Warn("The previous control cycle is still running - skip this cycle")
+				continue
+			}
+			go func() {
+				defer h.running.Store(false)
+				h.startControlLogic(ctx)
+			}()
 		case <-ctx.Done():
 			return nil
 		}
@@ -88,261 +176,558 @@ func (h *handler) startControlLogic(ctx context.Context) {
 	}
 
 	// update ocn store - to update neighbor or to add new cells coming
-	err = h.updateOcnStore(ctx)
+	err = h.storeGroup.Update(func() error {
+		return h.updateOcnStore(ctx)
+	})
+	if flushErr := h.ocnStore.Flush(ctx); flushErr != nil {
+		log.Error(flushErr)
+	}
 	if err != nil {
 		log.Error(err)
 		return
 	}
+	h.retainPingPongPairs(ctx)
 
-	// Get total num UE
-	totalNumUEs, err := h.getTotalNumUEs(ctx)
+	// decide from one consistent view of the measurement and Ocn stores
+	var snapshot *Snapshot
+	err = h.storeGroup.View(func() error {
+		snapshot, err = h.getSnapshot(ctx)
+		return err
+	})
 	if err != nil {
 		log.Error(err)
 		return
 	}
+	h.updateTriggers(ctx, snapshot)
+	shadow := h.isShadowMode(ctx)
+	state := h.getControlState(ctx, snapshot, shadow)
+	h.resolveHeldCells(ctx, state, snapshot)
 
-	// Get Cell IDs
-	cells, err := h.getCellList(ctx)
+	// run the algorithm over all cells
+	decision, err := h.algorithm.Compute(ctx, snapshot)
 	if err != nil {
 		log.Error(err)
 		return
 	}
-
-	// run control logic for each cell
-	for _, cell := range cells {
-		err = h.controlLogicEachCell(ctx, cell, cells, totalNumUEs)
-		if err != nil {
-			log.Error(err)
-			return
-		}
+	for ids, err := range decision.Errors {
+		log.Warnf("Algorithm %s could not decide Ocn for cell %v: %v", h.algorithm.Name(), ids, err)
 	}
-}
 
-func (h *handler) updateOcnStore(ctx context.Context) error {
-	ch := make(chan *storage.Entry)
-	go func(ch chan *storage.Entry) {
-		err := h.neighborMeasStore.ListElements(ctx, ch)
-		if err != nil {
-			log.Error(err)
-			close(ch)
-		}
-	}(ch)
+	// keep Ocns of the excluded cells and relations
+	h.applyExclusions(ctx, snapshot, decision)
 
-	for e := range ch {
-		ids := e.Key
-		neighborList := e.Value.([]storage.IDs)
+	// do not control quarantined cells until their backoff expires
+	h.removeQuarantinedCells(ctx, state, decision)
 
-		if _, err := h.ocnStore.Get(ctx, ids); err != nil {
-			// the new cells connected
-			_, err = h.ocnStore.Put(ctx, ids, &ocnstorage.OcnMap{
-				Value: make(map[storage.IDs]meastype.QOffsetRange),
-			})
-			if err != nil {
-				close(ch)
-				return err
-			}
-			for _, nIDs := range neighborList {
-				err = h.ocnStore.PutInnerMapElem(ctx, ids, nIDs, RcPreRanParamDefaultOCN)
-				if err != nil {
-					close(ch)
-					return err
-				}
-			}
-		} else {
-			// delete removed neighbor
-			inCh := make(chan ocnstorage.InnerEntry)
-			go func(inCh chan ocnstorage.InnerEntry) {
-				err := h.ocnStore.ListInnerElement(ctx, ids, inCh)
-				if err != nil {
-					log.Error(err)
-					close(ch)
-					close(inCh)
-				}
-			}(inCh)
+	// keep Ocn of the pairs frozen after ping-pong
+	h.holdFrozenPairs(ctx, state, decision)
 
-			for k := range inCh {
-				if !h.containsIDs(k.Key, neighborList) {
-					err = h.ocnStore.DeleteInnerElement(ctx, ids, k.Key)
-					close(ch)
-					return err
-				}
-			}
+	// keep Ocns of each neighbor pair consistent on both sides, without moving the sides held above
+	h.coordinatePairs(ctx, state, snapshot, decision)
 
-			// add new neighbor
-			for _, n := range neighborList {
-				if _, err = h.ocnStore.GetInnerMapElem(ctx, ids, n); err != nil {
-					err = h.ocnStore.PutInnerMapElem(ctx, ids, n, RcPreRanParamDefaultOCN)
-					if err != nil {
-						return err
-					}
-				}
-			}
-		}
+	h.recordProposals(ctx, decision)
+	if shadow {
+		log.Infof("Shadow mode - Ocns of %v cells are proposed but not applied", len(decision.Ocns))
+		// the proposals quarantine and freeze in the shadow state only; only the algorithm fails a cell there
+		h.detectPingPong(ctx, state, decision)
+		h.recordFailures(ctx, state, snapshot, decision)
+		h.recordHistory(ctx, snapshot, decision, true)
+		return
 	}
 
-	return nil
+	// apply Ocns for each cell
+	errs := h.applyDecision(ctx, decision)
+	for ids, err := range errs {
+		log.Errorf("Failed to apply Ocn for cell %v: %v", ids, err)
+		decision.Errors[ids] = err
+	}
+
+	h.detectPingPong(ctx, state, decision)
+	h.recordFailures(ctx, state, snapshot, decision)
+	h.recordHistory(ctx, snapshot, decision, false)
 }
 
-func (h *handler) containsIDs(ids storage.IDs, idsList []storage.IDs) bool {
-	for _, e := range idsList {
-		if e == ids {
-			return true
+// resolveHeldCells marks the cells none of whose Ocns can change in this cycle, so that algorithms with state,
+// e.g., the integral of the pid algorithm, do not accumulate for a decision which is not applied
+func (h *handler) resolveHeldCells(ctx context.Context, state *controlState, snapshot *Snapshot) {
+	snapshot.Held = make(map[storage.IDs]bool)
+	for _, ids := range snapshot.Cells {
+		held := true
+		for _, nIDs := range snapshot.Neighbors[ids] {
+			if !h.isOcnHeld(ctx, state, snapshot, ids, nIDs) {
+				held = false
+				break
+			}
+		}
+		if held && len(snapshot.Neighbors[ids]) > 0 {
+			snapshot.Held[ids] = true
 		}
 	}
-	return false
 }
 
-func (h *handler) getTotalNumUEs(ctx context.Context) (int, error) {
-	result := 0
-	ch := make(chan *storage.Entry)
-	go func(ch chan *storage.Entry) {
-		err := h.numUEsMeasStore.ListElements(ctx, ch)
+func (h *handler) removeQuarantinedCells(ctx context.Context, state *controlState, decision *Decision) {
+	now := time.Now()
+	cells := make([]storage.IDs, 0, len(decision.Ocns)+len(decision.Errors))
+	for ids := range decision.Ocns {
+		cells = append(cells, ids)
+	}
+	for ids := range decision.Errors {
+		cells = append(cells, ids)
+	}
+	for _, ids := range cells {
+		record, err := state.failureStore.Get(ctx, ids)
 		if err != nil {
-			log.Error(err)
-			close(ch)
+			continue
+		}
+		if record.IsQuarantined(now) {
+			log.Debugf("Cell %v is quarantined until %v after %v consecutive failures - skip", ids, record.QuarantinedUntil, record.ConsecutiveFailures)
+			delete(decision.Ocns, ids)
+			delete(decision.Errors, ids)
 		}
-	}(ch)
-	for e := range ch {
-		result += e.Value.(storage.Measurement).Value
 	}
-	return result, nil
 }
 
-func (h *handler) getCellList(ctx context.Context) ([]storage.IDs, error) {
-	result := make([]storage.IDs, 0)
-	ch := make(chan storage.IDs)
-	go func(chan storage.IDs) {
-		err := h.numUEsMeasStore.ListKeys(ctx, ch)
+func (h *handler) recordFailures(ctx context.Context, state *controlState, snapshot *Snapshot, decision *Decision) {
+	threshold, err := h.paramStore.Get(ctx, "quarantine_threshold")
+	if err != nil {
+		threshold = DefaultQuarantineThreshold
+	}
+	backoff, err := h.paramStore.Get(ctx, "quarantine_backoff")
+	if err != nil || backoff <= 0 {
+		backoff = DefaultQuarantineBackoff
+	}
+
+	for ids, cause := range decision.Errors {
+		record, err := state.failureStore.RecordFailure(ctx, ids, cause, threshold, time.Duration(backoff)*time.Second)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		if record.IsQuarantined(time.Now()) {
+			log.Warnf("Cell %v failed %v cycles in a row - quarantined until %v", ids, record.ConsecutiveFailures, record.QuarantinedUntil)
+		}
+	}
+	// a cell without error is healthy even if its Ocns did not change in this cycle;
+	// a quarantined cell was not controlled, so it stays quarantined
+	now := time.Now()
+	for _, ids := range snapshot.Cells {
+		if _, ok := decision.Errors[ids]; ok {
+			continue
+		}
+		if record, err := state.failureStore.Get(ctx, ids); err != nil || record.IsQuarantined(now) {
+			continue
+		}
+		err = state.failureStore.RecordSuccess(ctx, ids)
 		if err != nil {
 			log.Error(err)
-			close(ch)
 		}
-	}(ch)
-	for k := range ch {
-		result = append(result, k)
 	}
-	return result, nil
 }
 
-func (h *handler) controlLogicEachCell(ctx context.Context, ids storage.IDs, cells []storage.IDs, totalNumUEs int) error {
+// applyDecision applies the decision with a worker per E2 node so that a slow E2 node does not hold up the others
+func (h *handler) applyDecision(ctx context.Context, decision *Decision) map[storage.IDs]error {
+	maxWorkers, err := h.paramStore.Get(ctx, "max_workers")
+	if err != nil || maxWorkers <= 0 {
+		maxWorkers = DefaultMaxWorkers
+	}
+	nodeTimeout, err := h.paramStore.Get(ctx, "node_timeout")
+	if err != nil || nodeTimeout <= 0 {
+		nodeTimeout = DefaultNodeTimeout
+	}
 
-	targetThreshold, err := h.paramStore.Get(context.Background(), "target_threshold")
-	if err != nil {
-		return err
+	nodes := make(map[string][]storage.IDs)
+	for ids := range decision.Ocns {
+		nodes[ids.NodeID] = append(nodes[ids.NodeID], ids)
 	}
-	overloadThreshold, err := h.paramStore.Get(context.Background(), "overload_threshold")
+
+	errs := make(map[storage.IDs]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxWorkers)
+	for nodeID, cells := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(nodeID string, cells []storage.IDs) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// the subscriptions live as long as ctx; the timeout only bounds the wait for their acks
+			deadline := time.Now().Add(time.Duration(nodeTimeout) * time.Second)
+			nodeErrs := h.applyNodeOcns(ctx, cells, decision, deadline)
+			mu.Lock()
+			for ids, err := range nodeErrs {
+				errs[ids] = err
+			}
+			mu.Unlock()
+			log.Debugf("Finished to control E2 node %v", nodeID)
+		}(nodeID, cells)
+	}
+	wg.Wait()
+
+	// the Ocns committed by all workers are persisted at once
+	if err := h.ocnStore.Flush(ctx); err != nil {
+		log.Error(err)
+	}
+	return errs
+}
+
+// applyNodeOcns sends the Ocns of the cells in an E2 node and commits the Ocns of the cells
+// whose policy is set to the Ocn store all at once, so that the store keeps only the Ocns the E2 node has
+func (h *handler) applyNodeOcns(ctx context.Context, cells []storage.IDs, decision *Decision, deadline time.Time) map[storage.IDs]error {
+	errs := make(map[storage.IDs]error)
+	tx := h.ocnStore.Begin(ctx)
+	for _, ids := range cells {
+		ackTimeout := time.Until(deadline)
+		if ackTimeout <= 0 {
+			errs[ids] = errors.NewTimeout("E2 node %v timed out", ids.NodeID)
+			continue
+		}
+		err := h.e2PolicyHandler.SetPolicyForOcn(ctx, ids.NodeID, decision.Ocns[ids], ackTimeout)
+		if err != nil {
+			errs[ids] = err
+			continue
+		}
+		tx.PutInnerMapElems(ids, decision.Ocns[ids])
+	}
+
+	err := h.storeGroup.Update(func() error {
+		return tx.Commit(ctx)
+	})
 	if err != nil {
-		return err
+		for _, ids := range tx.Keys() {
+			errs[ids] = err
+		}
 	}
+	return errs
+}
 
-	ocnDeltaFactor, err := h.paramStore.Get(context.Background(), "delta_ocn")
+func (h *handler) getSnapshot(ctx context.Context) (*Snapshot, error) {
+	params, err := h.getParameters(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	neighbors, err := h.neighborMeasStore.Get(ctx, ids)
+	maxAge := time.Duration(params.MeasurementMaxAge) * time.Second
+
+	// Get total num UE
+	totalNumUEs, err := h.getTotalNumUEs(ctx, maxAge)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// calculate for each capacity and check sCell's and its neighbors' capacity
-	// if sCell load < target load threshold
-	// reduce Ocn
-	neighborList := neighbors.Value.([]storage.IDs)
-	numUEsSCell, err := h.numUE(ctx, ids.PlmnID, ids.CellID, cells)
+	// Get Cell IDs
+	cells, err := h.getCellList(ctx, maxAge)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	capSCell := h.getCapacity(1, totalNumUEs, numUEsSCell)
-	log.Debugf("Serving cell (%v) capacity: %v, load: %v / neighbor: %v / overload threshold %v, target threshold %v", ids, capSCell, 100-capSCell, cells, overloadThreshold, targetThreshold)
-	if 100-capSCell < targetThreshold && 100-capSCell < overloadThreshold {
-		tmpOcns := make(map[storage.IDs]meastype.QOffsetRange)
-		// send control message to reduce OCn for all neighbors
-		for _, nCellID := range neighborList {
-			ocn, err := h.ocnStore.GetInnerMapElem(ctx, ids, nCellID)
-			if err != nil {
-				return err
-			}
-			if ocn-meastype.QOffsetRange(ocnDeltaFactor) < meastype.QOffsetMinus24dB {
-				ocn = meastype.QOffsetMinus24dB
-			} else {
-				ocn = ocn - meastype.QOffsetRange(ocnDeltaFactor)
-			}
 
-			tmpOcns[nCellID] = ocn
-		}
-		err = h.e2PolicyHandler.SetPolicyForOcn(ctx, ids.NodeID, tmpOcns)
+	snapshot := &Snapshot{
+		Cells:       cells,
+		NumUEs:      make(map[storage.IDs]int),
+		TotalNumUEs: totalNumUEs,
+		Neighbors:   make(map[storage.IDs][]storage.IDs),
+		Ocns:        make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+		Params:      params,
+		Capacities:  make(map[storage.IDs]int),
+		PRBUsedDL:   make(map[storage.IDs]int),
+		PRBUsedUL:   make(map[storage.IDs]int),
+		Thresholds:  make(map[storage.IDs]thresholdstorage.Profile),
+	}
+
+	for _, cell := range cells {
+		numUEs, err := h.numUEsMeasStore.Get(ctx, cell)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		err = h.ocnStore.PutInnerMapElems(ctx, ids, tmpOcns)
+		snapshot.NumUEs[cell] = h.estimate(ctx, rnib.MetricNumUEs, cell, numUEs.Value)
+
+		neighbors, err := h.neighborMeasStore.Get(ctx, cell)
 		if err != nil {
-			return err
+			log.Warnf("there is no neighbor list for cell %v", cell)
+			continue
 		}
+		snapshot.Neighbors[cell] = neighbors.Value
+
+		snapshot.Ocns[cell] = h.getOcns(ctx, cell)
+	}
+	if h.loadEstimator != nil {
+		h.loadEstimator.Retain(ctx, cells)
+	}
+	h.forecastLoads(ctx, snapshot)
+	h.resolveCapacities(ctx, snapshot)
+	h.resolvePRBUsage(ctx, snapshot)
+	h.resolveThresholds(ctx, snapshot)
 
-		return nil
+	return snapshot, nil
+}
+
+// resolveThresholds puts the effective thresholds of the cells and their neighbors into the snapshot
+func (h *handler) resolveThresholds(ctx context.Context, snapshot *Snapshot) {
+	global := thresholdstorage.Profile{
+		TargetThreshold:   snapshot.Params.TargetThreshold,
+		OverloadThreshold: snapshot.Params.OverloadThreshold,
+	}
+	resolve := func(ids storage.IDs) {
+		if _, ok := snapshot.Thresholds[ids]; ok {
+			return
+		}
+		// neighbor IDs do not have E2 node ID; use the cell's IDs if this app controls it
+		full := ids
+		if cell, err := snapshot.FindCell(ids.PlmnID, ids.CellID); err == nil {
+			full = cell
+		}
+		profile, scope := h.thresholdStore.Resolve(ctx, full, global)
+		if scope == thresholdstorage.ScopeGlobal {
+			return
+		}
+		log.Debugf("Cell %v thresholds from %s override: %+v", ids, scope, profile)
+		snapshot.Thresholds[ids] = profile
+	}
+	for _, cell := range snapshot.Cells {
+		resolve(cell)
+		for _, nIDs := range snapshot.Neighbors[cell] {
+			resolve(nIDs)
+		}
 	}
+}
 
-	// if sCell load > overload threshold && nCell < target load threshold
-	// increase Ocn
-	if 100-capSCell > overloadThreshold {
-		tmpOcns := make(map[storage.IDs]meastype.QOffsetRange)
-		for _, nCellID := range neighborList {
-			ocn, err := h.ocnStore.GetInnerMapElem(ctx, ids, nCellID)
+func (h *handler) getParameters(ctx context.Context) (Parameters, error) {
+	targetThreshold, err := h.paramStore.Get(ctx, "target_threshold")
+	if err != nil {
+		return Parameters{}, err
+	}
+	overloadThreshold, err := h.paramStore.Get(ctx, "overload_threshold")
+	if err != nil {
+		return Parameters{}, err
+	}
+	ocnDeltaFactor, err := h.paramStore.Get(ctx, "delta_ocn")
+	if err != nil {
+		return Parameters{}, err
+	}
+	targetHysteresis, err := h.paramStore.Get(ctx, "target_hysteresis")
+	if err != nil {
+		targetHysteresis = 0
+	}
+	overloadHysteresis, err := h.paramStore.Get(ctx, "overload_hysteresis")
+	if err != nil {
+		overloadHysteresis = 0
+	}
+	timeToTrigger, err := h.paramStore.Get(ctx, "time_to_trigger")
+	if err != nil || timeToTrigger <= 0 {
+		timeToTrigger = DefaultTimeToTrigger
+	}
+	// PID gains are stored in hundredths since parameters are integers
+	kp, err := h.paramStore.Get(ctx, "pid_kp")
+	if err != nil {
+		kp = DefaultPIDKp
+	}
+	ki, err := h.paramStore.Get(ctx, "pid_ki")
+	if err != nil {
+		ki = DefaultPIDKi
+	}
+	kd, err := h.paramStore.Get(ctx, "pid_kd")
+	if err != nil {
+		kd = DefaultPIDKd
+	}
+	integralLimit, err := h.paramStore.Get(ctx, "pid_integral_limit")
+	if err != nil || integralLimit < 0 {
+		integralLimit = DefaultPIDIntegralLimit
+	}
+	pairMode, err := h.paramStore.Get(ctx, "pair_mode")
+	if err != nil {
+		pairMode = PairModeNone
+	}
+	pairSumBound, err := h.paramStore.Get(ctx, "pair_sum_bound")
+	if err != nil || pairSumBound < 0 {
+		pairSumBound = 0
+	}
+	migrationRate, err := h.paramStore.Get(ctx, "global_migration_rate")
+	if err != nil || migrationRate <= 0 {
+		migrationRate = DefaultGlobalMigrationRate
+	}
+	loadMetric, err := h.paramStore.Get(ctx, "load_metric")
+	if err != nil {
+		loadMetric = LoadMetricShare
+	}
+	defaultMaxUEs, err := h.paramStore.Get(ctx, "default_max_ues")
+	if err != nil || defaultMaxUEs < 0 {
+		defaultMaxUEs = 0
+	}
+	maxUEsPer100PRBs, err := h.paramStore.Get(ctx, "max_ues_per_100prbs")
+	if err != nil || maxUEsPer100PRBs < 0 {
+		maxUEsPer100PRBs = 0
+	}
+	maxAge, err := h.paramStore.Get(ctx, "measurement_max_age")
+	if err != nil || maxAge < 0 {
+		maxAge = DefaultMeasurementMaxAge
+	}
+	loadSource, err := h.paramStore.Get(ctx, "load_source")
+	if err != nil {
+		loadSource = LoadSourceCurrent
+	}
+	horizon, err := h.paramStore.Get(ctx, "forecast_horizon")
+	if err != nil || horizon <= 0 {
+		horizon = DefaultForecastHorizon
+	}
+	forecastAlpha, err := h.paramStore.Get(ctx, "forecast_alpha")
+	if err != nil || forecastAlpha <= 0 || forecastAlpha > 100 {
+		forecastAlpha = DefaultForecastAlpha
+	}
+	forecastBeta, err := h.paramStore.Get(ctx, "forecast_beta")
+	if err != nil || forecastBeta < 0 || forecastBeta > 100 {
+		forecastBeta = DefaultForecastBeta
+	}
+	weightUEs, err := h.paramStore.Get(ctx, "load_weight_ues")
+	if err != nil || weightUEs < 0 {
+		weightUEs = DefaultLoadWeightUEs
+	}
+	weightPRBDL, err := h.paramStore.Get(ctx, "load_weight_prb_dl")
+	if err != nil || weightPRBDL < 0 {
+		weightPRBDL = DefaultLoadWeightPRBDL
+	}
+	weightPRBUL, err := h.paramStore.Get(ctx, "load_weight_prb_ul")
+	if err != nil || weightPRBUL < 0 {
+		weightPRBUL = DefaultLoadWeightPRBUL
+	}
+	return Parameters{
+		TargetThreshold:     targetThreshold,
+		OverloadThreshold:   overloadThreshold,
+		DeltaOcn:            ocnDeltaFactor,
+		TargetHysteresis:    targetHysteresis,
+		OverloadHysteresis:  overloadHysteresis,
+		TimeToTrigger:       timeToTrigger,
+		PIDKp:               float64(kp) / 100,
+		PIDKi:               float64(ki) / 100,
+		PIDKd:               float64(kd) / 100,
+		PIDIntegralLimit:    float64(integralLimit),
+		PairMode:            pairMode,
+		PairSumBound:        pairSumBound,
+		GlobalMigrationRate: migrationRate,
+		LoadMetric:          loadMetric,
+		DefaultMaxUEs:       defaultMaxUEs,
+		MaxUEsPer100PRBs:    maxUEsPer100PRBs,
+		LoadWeightUEs:       weightUEs,
+		LoadWeightPRBDL:     weightPRBDL,
+		LoadWeightPRBUL:     weightPRBUL,
+		MeasurementMaxAge:   maxAge,
+		LoadSource:          loadSource,
+		ForecastHorizon:     horizon,
+		ForecastAlpha:       forecastAlpha,
+		ForecastBeta:        forecastBeta,
+	}, nil
+}
+
+func (h *handler) getOcns(ctx context.Context, ids storage.IDs) map[storage.IDs]meastype.QOffsetRange {
+	result := make(map[storage.IDs]meastype.QOffsetRange)
+	entries, err := h.ocnStore.ListInnerElement(ctx, ids)
+	if err != nil {
+		log.Warn(err)
+		return result
+	}
+	for _, e := range entries {
+		result[e.Key] = e.Value
+	}
+	return result
+}
+
+func (h *handler) updateOcnStore(ctx context.Context) error {
+	entries, err := h.neighborMeasStore.ListElements(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		ids := e.Key
+		neighborList := e.Value
+
+		if _, err := h.ocnStore.Get(ctx, ids); err != nil {
+			// the new cells connected
+			_, err = h.ocnStore.Put(ctx, ids, &ocnstorage.OcnMap{
+				Value: make(map[storage.IDs]meastype.QOffsetRange),
+			})
 			if err != nil {
 				return err
 			}
-			tmpOcns[nCellID] = ocn
-			numUEsNCell, err := h.numUE(ctx, nCellID.PlmnID, nCellID.CellID, cells)
-			if err != nil {
-				log.Warnf("there is no num(UEs) measurement value; this neighbor (plmnid-%v:cid-%v) may not be controlled by this xAPP; set num(UEs) to 0", nCellID.PlmnID, nCellID.CellID)
-			}
-			capNCell := h.getCapacity(1, totalNumUEs, numUEsNCell)
-			log.Debugf("Serving cell (%v)'s neighbor cell (%v) capacity: %v, load: %v / overload threshold %v, target threshold %v", ids, nCellID, capNCell, 100-capNCell, overloadThreshold, targetThreshold)
-			if 100-capNCell < targetThreshold {
-				if tmpOcns[nCellID]+meastype.QOffsetRange(ocnDeltaFactor) > meastype.QOffset24dB {
-					tmpOcns[nCellID] = meastype.QOffset24dB
-				} else {
-					tmpOcns[nCellID] = tmpOcns[nCellID] + meastype.QOffsetRange(ocnDeltaFactor)
+			for _, nIDs := range neighborList {
+				err = h.ocnStore.PutInnerMapElem(ctx, ids, nIDs, RcPreRanParamDefaultOCN)
+				if err != nil {
+					return err
 				}
 			}
+			continue
 		}
-		err = h.e2PolicyHandler.SetPolicyForOcn(ctx, ids.NodeID, tmpOcns)
+
+		// delete removed neighbor
+		inner, err := h.ocnStore.ListInnerElement(ctx, ids)
 		if err != nil {
 			return err
 		}
-		err = h.ocnStore.PutInnerMapElems(ctx, ids, tmpOcns)
-		if err != nil {
-			return err
+		for _, k := range inner {
+			if !h.containsIDs(k.Key, neighborList) {
+				err = h.ocnStore.DeleteInnerElement(ctx, ids, k.Key)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// add new neighbor
+		for _, n := range neighborList {
+			if _, err = h.ocnStore.GetInnerMapElem(ctx, ids, n); err != nil {
+				err = h.ocnStore.PutInnerMapElem(ctx, ids, n, RcPreRanParamDefaultOCN)
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-func (h *handler) getCapacity(denominationFactor float64, totalNumUEs int, numUEs int) int {
-	capacity := (1 - float64(numUEs)/(denominationFactor*float64(totalNumUEs))) * 100
-	return int(capacity)
+func (h *handler) containsIDs(ids storage.IDs, idsList []storage.IDs) bool {
+	for _, e := range idsList {
+		if e == ids {
+			return true
+		}
+	}
+	return false
 }
 
-func (h *handler) numUE(ctx context.Context, plmnID string, cid string, cells []storage.IDs) (int, error) {
-	storageID, err := h.findIDWithCGI(plmnID, cid, cells)
+// getTotalNumUEs gets the total filtered number of UEs in the cells whose measurement is not older than the max age
+func (h *handler) getTotalNumUEs(ctx context.Context, maxAge time.Duration) (int, error) {
+	now := time.Now()
+	entries, err := h.numUEsMeasStore.ListElements(ctx, storage.WithFilter(func(_ storage.IDs, measurement storage.Measurement) bool {
+		return !measurement.IsOlderThan(now, maxAge)
+	}))
 	if err != nil {
 		return 0, err
 	}
+	result := 0
+	for _, e := range entries {
+		result += h.estimate(ctx, rnib.MetricNumUEs, e.Key, e.Value)
+	}
+	return result, nil
+}
 
-	entry, err := h.numUEsMeasStore.Get(ctx, storageID)
-	if err != nil {
-		return 0, err
+// estimate returns the measurement filtered by the load estimator
+func (h *handler) estimate(ctx context.Context, metric string, ids storage.IDs, measurement storage.Measurement) int {
+	if h.loadEstimator == nil {
+		return measurement.Value
 	}
-	return entry.Value.(storage.Measurement).Value, nil
+	return h.loadEstimator.Estimate(ctx, metric, ids, measurement)
 }
 
-func (h *handler) findIDWithCGI(plmnid string, cid string, cells []storage.IDs) (storage.IDs, error) {
-	for _, cell := range cells {
-		if cell.PlmnID == plmnid && cell.CellID == cid {
-			return cell, nil
+// getCellList gets the cells whose num(UEs) measurement is not older than the max age;
+// the load of the other cells is unknown, so they are not controlled
+func (h *handler) getCellList(ctx context.Context, maxAge time.Duration) ([]storage.IDs, error) {
+	now := time.Now()
+	return h.numUEsMeasStore.ListKeys(ctx, storage.WithFilter(func(key storage.IDs, measurement storage.Measurement) bool {
+		if measurement.IsOlderThan(now, maxAge) {
+			log.Warnf("num(UEs) of cell %v was reported at %v - skip the cell whose load is unknown", key, measurement.Timestamp)
+			return false
 		}
-	}
-	return storage.IDs{}, errors.NewNotFound("ID not found with plmnid and cgi")
+		return true
+	}))
 }
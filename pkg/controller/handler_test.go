@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFailureHandler(t *testing.T, threshold int) *handler {
+	paramStore := paramstorage.NewStore()
+	assert.NoError(t, paramStore.Put(context.Background(), "quarantine_threshold", threshold))
+	return &handler{
+		paramStore:   paramStore,
+		failureStore: failurestorage.NewStore(),
+	}
+}
+
+// newTestDecision generates the decision which changes the Ocns of the given cells and fails the others
+func newTestDecision(snapshot *Snapshot, failed ...string) *Decision {
+	decision := &Decision{
+		Ocns:   make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+		Errors: make(map[storage.IDs]error),
+	}
+	for _, ids := range snapshot.Cells {
+		decision.Ocns[ids] = snapshot.Ocns[ids]
+	}
+	for _, cellID := range failed {
+		decision.Errors[testCell(cellID)] = errors.NewTimeout("no ack")
+	}
+	return decision
+}
+
+func TestRecordFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		// cycles are the failed cells of each cycle
+		cycles      [][]string
+		consecutive map[string]int
+		quarantined []string
+	}{
+		{
+			name:        "failures below the threshold",
+			threshold:   3,
+			cycles:      [][]string{{"a"}, {"a", "b"}},
+			consecutive: map[string]int{"a": 2, "b": 1},
+		},
+		{
+			name:        "failures at the threshold quarantine the cell",
+			threshold:   3,
+			cycles:      [][]string{{"a"}, {"a"}, {"a", "b"}},
+			consecutive: map[string]int{"a": 3, "b": 1},
+			quarantined: []string{"a"},
+		},
+		{
+			name:        "clean cycle resets the failures",
+			threshold:   3,
+			cycles:      [][]string{{"a"}, {"a"}, {}, {"a"}},
+			consecutive: map[string]int{"a": 1},
+		},
+		{
+			name:        "clean cycle does not release a quarantined cell",
+			threshold:   2,
+			cycles:      [][]string{{"a"}, {"a"}, {}},
+			consecutive: map[string]int{"a": 2},
+			quarantined: []string{"a"},
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestFailureHandler(t, test.threshold)
+			snapshot := newTestSnapshot(map[string]int{"a": 10, "b": 10, "c": 10}, nil, ocnStep(0), Parameters{})
+			for _, failed := range test.cycles {
+				h.recordFailures(ctx, h.getControlState(ctx, snapshot, false), snapshot, newTestDecision(snapshot, failed...))
+			}
+			for _, ids := range snapshot.Cells {
+				record, err := h.failureStore.Get(ctx, ids)
+				if err != nil {
+					assert.True(t, errors.IsNotFound(err))
+					assert.Zero(t, test.consecutive[ids.CellID])
+					continue
+				}
+				assert.Equal(t, test.consecutive[ids.CellID], record.ConsecutiveFailures, ids.CellID)
+				assert.Equal(t, contains(test.quarantined, ids.CellID), record.IsQuarantined(time.Now()), ids.CellID)
+			}
+
+			// the quarantined cells are skipped in the next decision
+			decision := newTestDecision(snapshot, "b")
+			h.removeQuarantinedCells(ctx, h.getControlState(ctx, snapshot, false), decision)
+			for _, ids := range snapshot.Cells {
+				_, ok := decision.Ocns[ids]
+				assert.Equal(t, !contains(test.quarantined, ids.CellID), ok, ids.CellID)
+			}
+		})
+	}
+}
+
+func TestQuarantineRelease(t *testing.T) {
+	ctx := context.Background()
+	h := newTestFailureHandler(t, 1)
+	snapshot := newTestSnapshot(map[string]int{"a": 10, "b": 10}, nil, ocnStep(0), Parameters{})
+	cell := testCell("a")
+	_, err := h.failureStore.RecordFailure(ctx, cell, errors.NewTimeout("no ack"), 1, 10*time.Millisecond)
+	assert.NoError(t, err)
+
+	decision := newTestDecision(snapshot)
+	h.removeQuarantinedCells(ctx, h.getControlState(ctx, snapshot, false), decision)
+	assert.NotContains(t, decision.Ocns, cell)
+
+	// the cell is controlled again after the backoff and a clean cycle resets it
+	time.Sleep(20 * time.Millisecond)
+	decision = newTestDecision(snapshot)
+	h.removeQuarantinedCells(ctx, h.getControlState(ctx, snapshot, false), decision)
+	assert.Contains(t, decision.Ocns, cell)
+	h.recordFailures(ctx, h.getControlState(ctx, snapshot, false), snapshot, decision)
+	record, err := h.failureStore.Get(ctx, cell)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, record.ConsecutiveFailures)
+	assert.Equal(t, 1, record.TotalFailures)
+}
+
+func contains(cellIDs []string, cellID string) bool {
+	for _, id := range cellIDs {
+		if id == cellID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// DefaultHistoryRetention is the default time in seconds for which the history of loads, Ocns and decisions is kept
+	DefaultHistoryRetention = 3600
+
+	// DefaultHistoryMaxPoints is the default number of points kept in each time series of the history
+	DefaultHistoryMaxPoints = 720
+)
+
+// recordHistory saves the loads, the Ocns and the decision of this cycle into the history store;
+// the Ocn of a pair is the applied one unless the cell failed or the decision was only proposed
+func (h *handler) recordHistory(ctx context.Context, snapshot *Snapshot, decision *Decision, shadow bool) {
+	if h.historyStore == nil {
+		return
+	}
+	now := time.Now()
+
+	for _, ids := range snapshot.Cells {
+		load, err := snapshot.Load(ids)
+		if err != nil {
+			continue
+		}
+		err = h.historyStore.Append(ctx, historystorage.Key{
+			Metric: historystorage.MetricLoad,
+			Cell:   ids,
+		}, historystorage.Point{
+			Time:  now,
+			Value: float64(load),
+		})
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	for ids, ocns := range snapshot.Ocns {
+		applied, ok := decision.Ocns[ids]
+		if _, failed := decision.Errors[ids]; shadow || failed {
+			ok = false
+		}
+		for nIDs, ocn := range ocns {
+			if ok {
+				if v, found := applied[nIDs]; found {
+					ocn = v
+				}
+			}
+			err := h.historyStore.Append(ctx, historystorage.Key{
+				Metric:   historystorage.MetricOcn,
+				Cell:     ids,
+				Neighbor: nIDs,
+			}, historystorage.Point{
+				Time:  now,
+				Value: float64(ocn),
+			})
+			if err != nil {
+				log.Error(err)
+			}
+		}
+	}
+
+	record := historystorage.DecisionRecord{
+		Time:      now,
+		Algorithm: h.algorithm.Name(),
+		Shadow:    shadow,
+		Ocns:      make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+		Errors:    make(map[storage.IDs]string),
+	}
+	for ids, ocns := range decision.Ocns {
+		record.Ocns[ids] = make(map[storage.IDs]meastype.QOffsetRange)
+		for k, v := range ocns {
+			record.Ocns[ids][k] = v
+		}
+	}
+	for ids, err := range decision.Errors {
+		record.Errors[ids] = err.Error()
+	}
+	err := h.historyStore.AppendDecision(ctx, record)
+	if err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+const (
+	// LoadMetricShare defines the load of a cell as its share of the UEs in all cells
+	LoadMetricShare = iota
+
+	// LoadMetricCapacity defines the load of a cell as its number of UEs over its own capacity
+	LoadMetricCapacity
+
+	// LoadMetricComposite defines the load of a cell as the weighted average of the UE load and the PRB usage
+	LoadMetricComposite
+)
+
+const (
+	// DefaultLoadWeightUEs is the default weight of the UE load in the composite load metric
+	DefaultLoadWeightUEs = 50
+
+	// DefaultLoadWeightPRBDL is the default weight of the downlink PRB usage in the composite load metric
+	DefaultLoadWeightPRBDL = 50
+
+	// DefaultLoadWeightPRBUL is the default weight of the uplink PRB usage in the composite load metric
+	DefaultLoadWeightPRBUL = 0
+)
+
+var loadMetrics = map[string]int{
+	"":          LoadMetricShare,
+	"share":     LoadMetricShare,
+	"capacity":  LoadMetricCapacity,
+	"composite": LoadMetricComposite,
+}
+
+// ParseLoadMetric parses the name of the load metric
+func ParseLoadMetric(name string) (int, error) {
+	if metric, ok := loadMetrics[name]; ok {
+		return metric, nil
+	}
+	return LoadMetricShare, errors.NewInvalid("unknown load metric %s", name)
+}
+
+// compositeLoad returns the weighted average of the UE load and the PRB usage of the cell;
+// the PRB usage which is not reported is left out of the average
+func (s *Snapshot) compositeLoad(cell storage.IDs, ueLoad int) int {
+	params := s.Params
+	sum := params.LoadWeightUEs * ueLoad
+	weights := params.LoadWeightUEs
+	if usage, ok := s.PRBUsedDL[cell]; ok {
+		sum += params.LoadWeightPRBDL * usage
+		weights += params.LoadWeightPRBDL
+	}
+	if usage, ok := s.PRBUsedUL[cell]; ok {
+		sum += params.LoadWeightPRBUL * usage
+		weights += params.LoadWeightPRBUL
+	}
+	if weights <= 0 {
+		return ueLoad
+	}
+	return sum / weights
+}
+
+// resolvePRBUsage puts the downlink and uplink PRB usage of each cell in percent into the snapshot.
+// The PRB usage KPI is the number of used PRBs, so it is divided by the number of PRBs in R-NIB;
+// if the number of PRBs is unknown, the KPI is taken as percent. The PRB usage older than the max age is left out.
+func (h *handler) resolvePRBUsage(ctx context.Context, snapshot *Snapshot) {
+	if snapshot.Params.LoadMetric != LoadMetricComposite {
+		return
+	}
+	maxAge := time.Duration(snapshot.Params.MeasurementMaxAge) * time.Second
+	for _, cell := range snapshot.Cells {
+		numPRBs := 0
+		if entry, err := h.numPRBsMeasStore.Get(ctx, cell); err == nil {
+			numPRBs = entry.Value.Value
+		}
+		if usage, ok := h.getPRBUsage(ctx, h.prbUsedDLMeasStore, rnib.MetricPRBDL, cell, numPRBs, maxAge); ok {
+			snapshot.PRBUsedDL[cell] = usage
+		}
+		if usage, ok := h.getPRBUsage(ctx, h.prbUsedULMeasStore, rnib.MetricPRBUL, cell, numPRBs, maxAge); ok {
+			snapshot.PRBUsedUL[cell] = usage
+		}
+	}
+	log.Debugf("PRB usage DL: %v / UL: %v", snapshot.PRBUsedDL, snapshot.PRBUsedUL)
+}
+
+func (h *handler) getPRBUsage(ctx context.Context, store storage.MeasurementStore, metric string, cell storage.IDs, numPRBs int, maxAge time.Duration) (int, bool) {
+	entry, err := store.Get(ctx, cell)
+	if err != nil {
+		return 0, false
+	}
+	measurement := entry.Value
+	if measurement.IsOlderThan(time.Now(), maxAge) {
+		log.Debugf("PRB usage of cell %v was reported at %v - leave it out", cell, measurement.Timestamp)
+		return 0, false
+	}
+	usage := h.estimate(ctx, metric, cell, measurement)
+	if numPRBs > 0 {
+		usage = usage * 100 / numPRBs
+	}
+	if usage > 100 {
+		usage = 100
+	}
+	return usage, true
+}
+
+// resolveCapacities puts the maximum number of UEs of each cell into the snapshot.
+// The capacity configured for the cell comes first; otherwise it is derived from the number of PRBs in R-NIB
+// with max_ues_per_100prbs, and falls back to default_max_ues.
+func (h *handler) resolveCapacities(ctx context.Context, snapshot *Snapshot) {
+	if snapshot.Params.LoadMetric == LoadMetricShare {
+		return
+	}
+	for _, cell := range snapshot.Cells {
+		if maxUEs, err := h.capacityStore.Get(ctx, cell); err == nil {
+			snapshot.Capacities[cell] = maxUEs
+			continue
+		}
+		if snapshot.Params.MaxUEsPer100PRBs > 0 {
+			numPRBs, err := h.numPRBsMeasStore.Get(ctx, cell)
+			if err == nil {
+				maxUEs := numPRBs.Value.Value * snapshot.Params.MaxUEsPer100PRBs / 100
+				if maxUEs > 0 {
+					snapshot.Capacities[cell] = maxUEs
+					continue
+				}
+			}
+		}
+		if snapshot.Params.DefaultMaxUEs > 0 {
+			snapshot.Capacities[cell] = snapshot.Params.DefaultMaxUEs
+			continue
+		}
+		log.Warnf("capacity of cell %v is unknown; use its share of all UEs as its load", cell)
+	}
+	log.Debugf("Cell capacities: %v", snapshot.Capacities)
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		metric    int
+		weights   [3]int
+		capacity  int
+		prbUsedDL int
+		prbUsedUL int
+		expected  int
+	}{
+		{name: "share of all UEs", metric: LoadMetricShare, capacity: 50, expected: 25},
+		{name: "UEs over capacity", metric: LoadMetricCapacity, capacity: 50, expected: 50},
+		{name: "unknown capacity falls back to the share", metric: LoadMetricCapacity, expected: 25},
+		{name: "composite of UEs and downlink PRB usage", metric: LoadMetricComposite, weights: [3]int{50, 50, 0}, capacity: 50, prbUsedDL: 80, prbUsedUL: 20, expected: 65},
+		{name: "composite of UEs and uplink PRB usage", metric: LoadMetricComposite, weights: [3]int{50, 0, 50}, capacity: 50, prbUsedDL: 80, prbUsedUL: 20, expected: 35},
+		{name: "composite of all", metric: LoadMetricComposite, weights: [3]int{2, 1, 1}, capacity: 50, prbUsedDL: 80, prbUsedUL: 20, expected: 50},
+		{name: "PRB usage not reported is left out", metric: LoadMetricComposite, weights: [3]int{50, 50, 50}, capacity: 50, prbUsedDL: -1, prbUsedUL: -1, expected: 50},
+		{name: "no weights", metric: LoadMetricComposite, capacity: 50, prbUsedDL: 80, expected: 50},
+		{name: "cell over its capacity", metric: LoadMetricCapacity, capacity: 20, expected: 125},
+	}
+
+	a := testCell("a")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := Parameters{
+				LoadMetric:      test.metric,
+				LoadWeightUEs:   test.weights[0],
+				LoadWeightPRBDL: test.weights[1],
+				LoadWeightPRBUL: test.weights[2],
+			}
+			snapshot := newTestSnapshot(map[string]int{"a": 25, "b": 75}, nil, meastype.QOffset0dB, params)
+			snapshot.Capacities = make(map[storage.IDs]int)
+			if test.capacity > 0 {
+				snapshot.Capacities[a] = test.capacity
+			}
+			snapshot.PRBUsedDL = make(map[storage.IDs]int)
+			if test.prbUsedDL >= 0 {
+				snapshot.PRBUsedDL[a] = test.prbUsedDL
+			}
+			snapshot.PRBUsedUL = make(map[storage.IDs]int)
+			if test.prbUsedUL >= 0 {
+				snapshot.PRBUsedUL[a] = test.prbUsedUL
+			}
+			load, err := snapshot.Load(a)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, load)
+		})
+	}
+}
+
+func TestSnapshotLoadUnknown(t *testing.T) {
+	snapshot := newTestSnapshot(map[string]int{"a": 25}, []string{"b"}, meastype.QOffset0dB, Parameters{})
+	_, err := snapshot.Load(testCell("b"))
+	assert.True(t, errors.IsNotFound(err))
+	_, err = snapshot.Load(testCell("c"))
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestParseLoadMetric(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int
+	}{
+		{name: "", expected: LoadMetricShare},
+		{name: "share", expected: LoadMetricShare},
+		{name: "capacity", expected: LoadMetricCapacity},
+		{name: "composite", expected: LoadMetricComposite},
+	}
+
+	for _, test := range tests {
+		metric, err := ParseLoadMetric(test.name)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, metric)
+	}
+
+	_, err := ParseLoadMetric("unknown")
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// PairModeNone does not coordinate the Ocns of a neighbor pair
+	PairModeNone = iota
+
+	// PairModeAntisymmetric keeps Ocn(A->B) = -Ocn(B->A)
+	PairModeAntisymmetric
+
+	// PairModeBoundedSum keeps |Ocn(A->B) + Ocn(B->A)| <= pair sum bound
+	PairModeBoundedSum
+)
+
+var pairModes = map[string]int{
+	"":              PairModeNone,
+	"none":          PairModeNone,
+	"antisymmetric": PairModeAntisymmetric,
+	"bounded":       PairModeBoundedSum,
+}
+
+// ParsePairMode parses the name of the pair coordination mode
+func ParsePairMode(name string) (int, error) {
+	if mode, ok := pairModes[name]; ok {
+		return mode, nil
+	}
+	return PairModeNone, errors.NewInvalid("unknown pair mode %s", name)
+}
+
+// PairSumBound returns the largest |Ocn(A->B) + Ocn(B->A)| allowed in the pair mode;
+// the Ocns are counted in steps of the Ocn range from QOffset0dB
+func PairSumBound(mode int, bound int) int {
+	if mode == PairModeBoundedSum {
+		return bound
+	}
+	return 0
+}
+
+// coordinatePairs makes the Ocns of each neighbor relation consistent on both sides.
+// Each relation is handled once per cycle with the Ocns desired by the algorithm on both sides,
+// so that two cells do not push UEs towards each other. It runs after the exclusions, quarantine and frozen pairs
+// are applied; the Ocn of an excluded, quarantined or frozen side is kept and only the other side is moved.
+func (h *handler) coordinatePairs(ctx context.Context, state *controlState, snapshot *Snapshot, decision *Decision) {
+	mode := snapshot.Params.PairMode
+	if mode == PairModeNone {
+		return
+	}
+	bound := PairSumBound(mode, snapshot.Params.PairSumBound)
+
+	visited := make(map[ocnPair]bool)
+	for _, a := range snapshot.Cells {
+		for _, nB := range snapshot.Neighbors[a] {
+			b, err := snapshot.FindCell(nB.PlmnID, nB.CellID)
+			if err != nil {
+				// the neighbor is not controlled by this app
+				continue
+			}
+			nA, ok := findNeighbor(snapshot.Neighbors[b], a)
+			if !ok {
+				// the relation is not mutual
+				continue
+			}
+			if visited[ocnPair{sCell: b, nCell: nA}] {
+				continue
+			}
+			visited[ocnPair{sCell: a, nCell: nB}] = true
+
+			ab, errAB := desiredOcn(snapshot, decision, a, nB)
+			ba, errBA := desiredOcn(snapshot, decision, b, nA)
+			if errAB != nil || errBA != nil {
+				continue
+			}
+			holdAB := h.isOcnHeld(ctx, state, snapshot, a, nB)
+			holdBA := h.isOcnHeld(ctx, state, snapshot, b, nA)
+			newAB, newBA := coordinatePair(ab, ba, bound, holdAB, holdBA)
+			if newAB == ab && newBA == ba {
+				continue
+			}
+			log.Debugf("Coordinate Ocn pair %v<->%v: (%v, %v) -> (%v, %v)", a, b, ab, ba, newAB, newBA)
+			setDesiredOcn(snapshot, decision, a, nB, newAB)
+			setDesiredOcn(snapshot, decision, b, nA, newBA)
+		}
+	}
+}
+
+// coordinatePair returns the Ocns of a pair whose sum is within the bound; the excess is split between both sides
+// unless one of them is held, in which case the other side takes all of it
+func coordinatePair(ab meastype.QOffsetRange, ba meastype.QOffsetRange, bound int, holdAB bool, holdBA bool) (meastype.QOffsetRange, meastype.QOffsetRange) {
+	a := int(ab - meastype.QOffset0dB)
+	b := int(ba - meastype.QOffset0dB)
+	sum := a + b
+	var excess int
+	if sum > bound {
+		excess = sum - bound
+	} else if sum < -bound {
+		excess = sum + bound
+	} else {
+		return ab, ba
+	}
+	switch {
+	case holdAB && holdBA:
+		return ab, ba
+	case holdAB:
+		b -= excess
+	case holdBA:
+		a -= excess
+	default:
+		a -= excess / 2
+		b -= excess - excess/2
+	}
+	return clampOcn(int(meastype.QOffset0dB) + a), clampOcn(int(meastype.QOffset0dB) + b)
+}
+
+// isOcnHeld returns true if the Ocn from the cell to the neighbor must not be changed in this cycle,
+// since the cell is excluded or quarantined, or the relation is excluded or frozen
+func (h *handler) isOcnHeld(ctx context.Context, state *controlState, snapshot *Snapshot, ids storage.IDs, nIDs storage.IDs) bool {
+	if h.exclusionStore.IsCellExcluded(ctx, ids) {
+		return true
+	}
+	// neighbor IDs do not have E2 node ID; use the cell's IDs if this app controls it
+	nCell, err := snapshot.FindCell(nIDs.PlmnID, nIDs.CellID)
+	if err != nil {
+		nCell = nIDs
+	}
+	if h.exclusionStore.IsRelationExcluded(ctx, ids, nCell) {
+		return true
+	}
+	if record, err := state.failureStore.Get(ctx, ids); err == nil && record.IsQuarantined(time.Now()) {
+		return true
+	}
+	return state.ocnStore.IsInnerElementFrozen(ctx, ids, nIDs)
+}
+
+func findNeighbor(neighbors []storage.IDs, ids storage.IDs) (storage.IDs, bool) {
+	for _, n := range neighbors {
+		if n.PlmnID == ids.PlmnID && n.CellID == ids.CellID {
+			return n, true
+		}
+	}
+	return storage.IDs{}, false
+}
+
+func desiredOcn(snapshot *Snapshot, decision *Decision, ids storage.IDs, nIDs storage.IDs) (meastype.QOffsetRange, error) {
+	if ocn, ok := decision.Ocns[ids][nIDs]; ok {
+		return ocn, nil
+	}
+	return snapshot.Ocn(ids, nIDs)
+}
+
+func setDesiredOcn(snapshot *Snapshot, decision *Decision, ids storage.IDs, nIDs storage.IDs, ocn meastype.QOffsetRange) {
+	if _, ok := decision.Ocns[ids]; !ok {
+		// a policy carries the Ocns of all neighbors, so start from the current Ocns
+		ocns := make(map[storage.IDs]meastype.QOffsetRange)
+		for k, v := range snapshot.Ocns[ids] {
+			ocns[k] = v
+		}
+		decision.Ocns[ids] = ocns
+	}
+	decision.Ocns[ids][nIDs] = ocn
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+// ocnStep returns the Ocn the number of steps away from QOffset0dB
+func ocnStep(step int) meastype.QOffsetRange {
+	return meastype.QOffsetRange(int(meastype.QOffset0dB) + step)
+}
+
+func TestCoordinatePair(t *testing.T) {
+	tests := []struct {
+		name       string
+		ab, ba     int
+		bound      int
+		holdAB     bool
+		holdBA     bool
+		expectedAB int
+		expectedBA int
+	}{
+		{name: "antisymmetric pair is kept", ab: 3, ba: -3, expectedAB: 3, expectedBA: -3},
+		{name: "positive excess is split", ab: 4, ba: 2, expectedAB: 1, expectedBA: -1},
+		{name: "odd excess is split", ab: 3, ba: 0, expectedAB: 2, expectedBA: -2},
+		{name: "negative excess is split", ab: -4, ba: -2, expectedAB: -1, expectedBA: 1},
+		{name: "sum within the bound is kept", ab: 3, ba: 1, bound: 4, expectedAB: 3, expectedBA: 1},
+		{name: "sum is reduced to the bound", ab: 5, ba: 3, bound: 4, expectedAB: 3, expectedBA: 1},
+		{name: "negative sum is raised to the bound", ab: -5, ba: -3, bound: 4, expectedAB: -3, expectedBA: -1},
+		{name: "held A->B moves only B->A", ab: 4, ba: 2, holdAB: true, expectedAB: 4, expectedBA: -4},
+		{name: "held B->A moves only A->B", ab: 4, ba: 2, holdBA: true, expectedAB: -2, expectedBA: 2},
+		{name: "both held are kept", ab: 4, ba: 2, holdAB: true, holdBA: true, expectedAB: 4, expectedBA: 2},
+		{name: "Ocns at the ends of the range", ab: 15, ba: 15, expectedAB: 0, expectedBA: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ab, ba := coordinatePair(ocnStep(test.ab), ocnStep(test.ba), test.bound, test.holdAB, test.holdBA)
+			assert.Equal(t, ocnStep(test.expectedAB), ab)
+			assert.Equal(t, ocnStep(test.expectedBA), ba)
+		})
+	}
+}
+
+func TestParsePairMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int
+		bound    int
+	}{
+		{name: "", expected: PairModeNone},
+		{name: "none", expected: PairModeNone},
+		{name: "antisymmetric", expected: PairModeAntisymmetric},
+		{name: "bounded", expected: PairModeBoundedSum, bound: 4},
+	}
+
+	for _, test := range tests {
+		mode, err := ParsePairMode(test.name)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, mode)
+		assert.Equal(t, test.bound, PairSumBound(mode, 4))
+	}
+
+	_, err := ParsePairMode("unknown")
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"math"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// PIDAlgorithmName is the name of the PID-style algorithm
+	PIDAlgorithmName = "pid"
+
+	// DefaultPIDKp is the default proportional gain in hundredths
+	DefaultPIDKp = 10
+
+	// DefaultPIDKi is the default integral gain in hundredths
+	DefaultPIDKi = 2
+
+	// DefaultPIDKd is the default derivative gain in hundredths
+	DefaultPIDKd = 0
+
+	// DefaultPIDIntegralLimit is the default limit of the accumulated load error
+	DefaultPIDIntegralLimit = 200
+
+	// maxOcnStep is the largest Ocn step which moves Ocn from one end of the range to the other
+	maxOcnStep = int(meastype.QOffset24dB - meastype.QOffsetMinus24dB)
+)
+
+func init() {
+	RegisterAlgorithm(PIDAlgorithmName, NewPIDAlgorithm)
+}
+
+// NewPIDAlgorithm generates the PID-style algorithm whose Ocn step scales with the error
+// between the load of a cell and the target threshold
+func NewPIDAlgorithm() Algorithm {
+	return &pidAlgorithm{
+		states: make(map[storage.IDs]*pidState),
+	}
+}
+
+type pidState struct {
+	integral  float64
+	prevError float64
+}
+
+type pidAlgorithm struct {
+	states map[storage.IDs]*pidState
+}
+
+func (a *pidAlgorithm) Name() string {
+	return PIDAlgorithmName
+}
+
+func (a *pidAlgorithm) Compute(_ context.Context, snapshot *Snapshot) (*Decision, error) {
+	decision := NewDecision()
+	cells := make(map[storage.IDs]bool)
+	for _, ids := range snapshot.Cells {
+		cells[ids] = true
+		ocns, err := a.computeEachCell(ids, snapshot)
+		if err != nil {
+			decision.Errors[ids] = err
+			continue
+		}
+		if ocns != nil {
+			decision.Ocns[ids] = ocns
+		}
+	}
+
+	// reset the state of the cells which are gone
+	for ids := range a.states {
+		if !cells[ids] {
+			delete(a.states, ids)
+		}
+	}
+	return decision, nil
+}
+
+func (a *pidAlgorithm) computeEachCell(ids storage.IDs, snapshot *Snapshot) (map[storage.IDs]meastype.QOffsetRange, error) {
+	params := snapshot.Params
+	load, err := snapshot.Load(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	state, ok := a.states[ids]
+	if !ok {
+		state = &pidState{}
+	}
+
+	// positive error means that the cell has more load than the target
+	targetThreshold := snapshot.TargetThreshold(ids)
+	e := float64(load - targetThreshold)
+	derivative := e - state.prevError
+
+	integral := state.integral + e
+	u := params.PIDKp*e + params.PIDKi*integral + params.PIDKd*derivative
+	// anti-windup: stop accumulating the error while the output is saturated or not applied to the held cell
+	nextIntegral := state.integral
+	if math.Abs(u) <= float64(maxOcnStep) && !snapshot.Held[ids] {
+		nextIntegral = math.Max(-params.PIDIntegralLimit, math.Min(params.PIDIntegralLimit, integral))
+	}
+	// the state moves on only if the output is decided, so that a cell failing this cycle does not accumulate
+	commit := func() {
+		state.prevError = e
+		state.integral = nextIntegral
+		a.states[ids] = state
+	}
+
+	step := int(math.Round(u))
+	if step > maxOcnStep {
+		step = maxOcnStep
+	} else if step < -maxOcnStep {
+		step = -maxOcnStep
+	}
+	log.Debugf("Serving cell (%v) load: %v / target threshold %v / PID output %v, step %v", ids, load, targetThreshold, u, step)
+	if step == 0 {
+		commit()
+		return nil, nil
+	}
+
+	tmpOcns := make(map[storage.IDs]meastype.QOffsetRange)
+	for _, nCellID := range snapshot.Neighbors[ids] {
+		ocn, err := snapshot.Ocn(ids, nCellID)
+		if err != nil {
+			return nil, err
+		}
+		// offload only to the neighbors below target; keep UEs from all neighbors
+		if step > 0 && !snapshot.IsUnderloaded(nCellID) {
+			tmpOcns[nCellID] = ocn
+			continue
+		}
+		tmpOcns[nCellID] = clampOcn(int(ocn) + step)
+	}
+	commit()
+	return tmpOcns, nil
+}
+
+// clampOcn clamps the Ocn into the range from QOffsetMinus24dB to QOffset24dB
+func clampOcn(ocn int) meastype.QOffsetRange {
+	if ocn < int(meastype.QOffsetMinus24dB) {
+		return meastype.QOffsetMinus24dB
+	}
+	if ocn > int(meastype.QOffset24dB) {
+		return meastype.QOffset24dB
+	}
+	return meastype.QOffsetRange(ocn)
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPIDAlgorithm(t *testing.T) {
+	type cycle struct {
+		held     bool
+		step     int
+		integral float64
+	}
+	tests := []struct {
+		name   string
+		params Parameters
+		cycles []cycle
+	}{
+		{
+			name:   "proportional step",
+			params: Parameters{PIDKp: 0.1, PIDIntegralLimit: 200},
+			cycles: []cycle{
+				{step: 3, integral: 31},
+				{step: 3, integral: 62},
+			},
+		},
+		{
+			name:   "integral accumulates the error",
+			params: Parameters{PIDKi: 0.1, PIDIntegralLimit: 200},
+			cycles: []cycle{
+				{step: 3, integral: 31},
+				{step: 6, integral: 62},
+				{step: 9, integral: 93},
+			},
+		},
+		{
+			name:   "integral is limited",
+			params: Parameters{PIDKi: 0.1, PIDIntegralLimit: 40},
+			cycles: []cycle{
+				{step: 3, integral: 31},
+				{step: 6, integral: 40},
+				{step: 7, integral: 40},
+			},
+		},
+		{
+			name:   "integral does not accumulate while the step is saturated",
+			params: Parameters{PIDKp: 1, PIDKi: 0.1, PIDIntegralLimit: 200},
+			cycles: []cycle{
+				{step: maxOcnStep, integral: 0},
+				{step: maxOcnStep, integral: 0},
+			},
+		},
+		{
+			name:   "integral does not accumulate while the cell is held",
+			params: Parameters{PIDKi: 0.1, PIDIntegralLimit: 200},
+			cycles: []cycle{
+				{held: true, step: 3, integral: 0},
+				{held: true, step: 3, integral: 0},
+				{step: 3, integral: 31},
+			},
+		},
+	}
+
+	a := testCell("a")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.params.TargetThreshold = 50
+			algorithm := NewPIDAlgorithm()
+			for i, c := range test.cycles {
+				// the load of a is 81 and the load of b is 20
+				snapshot := newTestSnapshot(map[string]int{"a": 80, "b": 20}, nil, meastype.QOffset0dB, test.params)
+				if c.held {
+					snapshot.Held = map[storage.IDs]bool{a: true}
+				}
+				decision, err := algorithm.Compute(context.Background(), snapshot)
+				assert.NoError(t, err)
+				assert.Equal(t, clampOcn(int(meastype.QOffset0dB)+c.step), decision.Ocns[a][testNeighbor("b")], "cycle %d", i)
+				assert.InDelta(t, c.integral, algorithm.(*pidAlgorithm).states[a].integral, 1e-9, "cycle %d", i)
+			}
+		})
+	}
+}
+
+func TestPIDAlgorithmNeighbors(t *testing.T) {
+	params := Parameters{
+		TargetThreshold:  25,
+		PIDKp:            0.1,
+		PIDIntegralLimit: 200,
+	}
+	// the loads of a, b and c are 60, 30 and 10
+	snapshot := newTestSnapshot(map[string]int{"a": 60, "b": 30, "c": 10}, nil, meastype.QOffset0dB, params)
+	decision, err := NewPIDAlgorithm().Compute(context.Background(), snapshot)
+	assert.NoError(t, err)
+	assertDecision(t, map[string]map[string]meastype.QOffsetRange{
+		// offload only to the neighbor below target
+		"a": {"b": meastype.QOffset0dB, "c": meastype.QOffset4dB},
+		"b": {"a": meastype.QOffset0dB, "c": meastype.QOffset1dB},
+		// keep UEs from all neighbors
+		"c": {"a": meastype.QOffsetMinus2dB, "b": meastype.QOffsetMinus2dB},
+	}, decision)
+}
+
+func TestPIDAlgorithmRemovedCell(t *testing.T) {
+	params := Parameters{
+		TargetThreshold:  50,
+		PIDKi:            0.1,
+		PIDIntegralLimit: 200,
+	}
+	algorithm := NewPIDAlgorithm()
+	_, err := algorithm.Compute(context.Background(), newTestSnapshot(map[string]int{"a": 80, "b": 20}, nil, meastype.QOffset0dB, params))
+	assert.NoError(t, err)
+	assert.Contains(t, algorithm.(*pidAlgorithm).states, testCell("b"))
+
+	_, err = algorithm.Compute(context.Background(), newTestSnapshot(map[string]int{"a": 80}, nil, meastype.QOffset0dB, params))
+	assert.NoError(t, err)
+	assert.NotContains(t, algorithm.(*pidAlgorithm).states, testCell("b"))
+}
+
+func TestPIDAlgorithmMissingOcn(t *testing.T) {
+	params := Parameters{
+		TargetThreshold:  50,
+		PIDKp:            0.1,
+		PIDKi:            0.1,
+		PIDIntegralLimit: 200,
+	}
+	a := testCell("a")
+	algorithm := NewPIDAlgorithm()
+	_, err := algorithm.Compute(context.Background(), newTestSnapshot(map[string]int{"a": 70, "b": 30}, nil, meastype.QOffset0dB, params))
+	assert.NoError(t, err)
+	state := *algorithm.(*pidAlgorithm).states[a]
+
+	// the cell fails without changing its state, as if the cycle did not happen
+	snapshot := newTestSnapshot(map[string]int{"a": 90, "b": 10}, nil, meastype.QOffset0dB, params)
+	delete(snapshot.Ocns[a], testNeighbor("b"))
+	decision, err := algorithm.Compute(context.Background(), snapshot)
+	assert.NoError(t, err)
+	assert.True(t, errors.IsNotFound(decision.Errors[a]))
+	assert.Equal(t, state, *algorithm.(*pidAlgorithm).states[a])
+
+	// a new cell failing in its first cycle gets no state
+	snapshot = newTestSnapshot(map[string]int{"a": 10, "b": 10, "c": 180}, nil, meastype.QOffset0dB, params)
+	delete(snapshot.Ocns[testCell("c")], testNeighbor("a"))
+	decision, err = algorithm.Compute(context.Background(), snapshot)
+	assert.NoError(t, err)
+	assert.Contains(t, decision.Errors, testCell("c"))
+	assert.NotContains(t, algorithm.(*pidAlgorithm).states, testCell("c"))
+}
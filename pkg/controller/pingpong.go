@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+const (
+	// DefaultPingPongWindow is the default time window in seconds in which Ocn changes are examined
+	DefaultPingPongWindow = 300
+
+	// DefaultPingPongCount is the default number of sign changes in the window to detect a ping-pong
+	DefaultPingPongCount = 3
+
+	// DefaultPingPongCooldown is the default time in seconds for which a ping-pong pair is frozen
+	DefaultPingPongCooldown = 600
+)
+
+type ocnPair struct {
+	sCell storage.IDs
+	nCell storage.IDs
+}
+
+type ocnChange struct {
+	delta int
+	time  time.Time
+}
+
+// pingPongDetector detects the serving and neighbor cell pairs whose Ocn goes up and down repeatedly
+type pingPongDetector struct {
+	history map[ocnPair][]ocnChange
+	mu      sync.Mutex
+}
+
+func newPingPongDetector() *pingPongDetector {
+	return &pingPongDetector{
+		history: make(map[ocnPair][]ocnChange),
+	}
+}
+
+// record records an Ocn change of the pair and returns true if the sign of the change alternated
+// at least count times within the window
+func (d *pingPongDetector) record(pair ocnPair, delta int, now time.Time, window time.Duration, count int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	changes := append(d.history[pair], ocnChange{
+		delta: delta,
+		time:  now,
+	})
+	i := 0
+	for i < len(changes) && now.Sub(changes[i].time) > window {
+		i++
+	}
+	changes = changes[i:]
+
+	alternations := 0
+	for j := 1; j < len(changes); j++ {
+		if (changes[j].delta > 0) != (changes[j-1].delta > 0) {
+			alternations++
+		}
+	}
+	if alternations >= count {
+		delete(d.history, pair)
+		return true
+	}
+	d.history[pair] = changes
+	return false
+}
+
+// retain drops the history of the pairs which are not in the given relations any longer,
+// e.g., since the serving or the neighbor cell was removed
+func (d *pingPongDetector) retain(relations map[ocnPair]bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for pair := range d.history {
+		if !relations[pair] {
+			delete(d.history, pair)
+		}
+	}
+}
+
+// retainPingPongPairs keeps the ping-pong history of the relations in the Ocn store only
+func (h *handler) retainPingPongPairs(ctx context.Context) {
+	entries, err := h.ocnStore.ListAllInnerElement(ctx)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	relations := make(map[ocnPair]bool, len(entries))
+	for _, e := range entries {
+		relations[ocnPair{sCell: e.Key, nCell: e.Value.Key}] = true
+	}
+	h.pingPong.retain(relations)
+	h.shadowPingPong.retain(relations)
+}
+
+// holdFrozenPairs keeps the current Ocn of the frozen pairs in the decision
+func (h *handler) holdFrozenPairs(ctx context.Context, state *controlState, decision *Decision) {
+	for ids, ocns := range decision.Ocns {
+		for nIDs := range ocns {
+			if !state.ocnStore.IsInnerElementFrozen(ctx, ids, nIDs) {
+				continue
+			}
+			if ocn, err := state.Ocn(ids, nIDs); err == nil {
+				log.Debugf("Ocn from %v to %v is frozen - keep %v", ids, nIDs, ocn)
+				ocns[nIDs] = ocn
+			}
+		}
+	}
+}
+
+// detectPingPong feeds the Ocn changes of the decision to the ping-pong detector of the state
+// and freezes the pairs ping-ponging
+func (h *handler) detectPingPong(ctx context.Context, state *controlState, decision *Decision) {
+	count, err := h.paramStore.Get(ctx, "pingpong_count")
+	if err != nil {
+		count = DefaultPingPongCount
+	}
+	if count <= 0 {
+		return
+	}
+	window, err := h.paramStore.Get(ctx, "pingpong_window")
+	if err != nil || window <= 0 {
+		window = DefaultPingPongWindow
+	}
+	cooldown, err := h.paramStore.Get(ctx, "pingpong_cooldown")
+	if err != nil || cooldown <= 0 {
+		cooldown = DefaultPingPongCooldown
+	}
+
+	now := time.Now()
+	for ids, ocns := range decision.Ocns {
+		if _, ok := decision.Errors[ids]; ok {
+			continue
+		}
+		for nIDs, ocn := range ocns {
+			prev, err := state.Ocn(ids, nIDs)
+			if err != nil || ocn == prev {
+				continue
+			}
+			pair := ocnPair{
+				sCell: ids,
+				nCell: nIDs,
+			}
+			if !state.pingPong.record(pair, int(ocn-prev), now, time.Duration(window)*time.Second, count) {
+				continue
+			}
+			until := now.Add(time.Duration(cooldown) * time.Second)
+			log.Warnf("Ocn from %v to %v ping-pongs - freeze it until %v", ids, nIDs, until)
+			err = state.ocnStore.FreezeInnerElement(ctx, ids, nIDs, until)
+			if err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingPongDetectorRecord(t *testing.T) {
+	type change struct {
+		delta int
+		at    int
+	}
+	tests := []struct {
+		name     string
+		changes  []change
+		expected []bool
+	}{
+		{
+			name:     "sign flips within the window",
+			changes:  []change{{delta: 1, at: 0}, {delta: -1, at: 10}, {delta: 1, at: 20}},
+			expected: []bool{false, false, true},
+		},
+		{
+			name:     "same sign does not alternate",
+			changes:  []change{{delta: 1, at: 0}, {delta: 2, at: 10}, {delta: 1, at: 20}},
+			expected: []bool{false, false, false},
+		},
+		{
+			name:     "sign flips outside the window",
+			changes:  []change{{delta: 1, at: 0}, {delta: -1, at: 70}, {delta: 1, at: 140}},
+			expected: []bool{false, false, false},
+		},
+		{
+			name:     "changes older than the window are dropped",
+			changes:  []change{{delta: 1, at: 0}, {delta: -1, at: 10}, {delta: 1, at: 65}},
+			expected: []bool{false, false, false},
+		},
+		{
+			name:     "history starts over after a detection",
+			changes:  []change{{delta: 1, at: 0}, {delta: -1, at: 10}, {delta: 1, at: 20}, {delta: -1, at: 30}},
+			expected: []bool{false, false, true, false},
+		},
+	}
+
+	pair := ocnPair{sCell: testCell("a"), nCell: testNeighbor("b")}
+	start := time.Now()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newPingPongDetector()
+			for i, c := range test.changes {
+				now := start.Add(time.Duration(c.at) * time.Second)
+				assert.Equal(t, test.expected[i], d.record(pair, c.delta, now, time.Minute, 2), "change %d", i)
+			}
+		})
+	}
+}
+
+func newTestPingPongHandler(t *testing.T, params map[string]int) *handler {
+	ctx := context.Background()
+	paramStore := paramstorage.NewStore()
+	for key, value := range params {
+		assert.NoError(t, paramStore.Put(ctx, key, value))
+	}
+	return &handler{
+		ocnStore:   ocnstorage.NewStore(),
+		paramStore: paramStore,
+		pingPong:   newPingPongDetector(),
+	}
+}
+
+func TestDetectPingPong(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]int
+		deltas []int
+		failed bool
+		frozen bool
+	}{
+		{
+			name:   "sign flips freeze the pair",
+			params: map[string]int{"pingpong_count": 2, "pingpong_cooldown": 60},
+			deltas: []int{1, -1, 1},
+			frozen: true,
+		},
+		{
+			name:   "too few sign flips",
+			params: map[string]int{"pingpong_count": 3, "pingpong_cooldown": 60},
+			deltas: []int{1, -1, 1},
+		},
+		{
+			name:   "steady changes",
+			params: map[string]int{"pingpong_count": 2, "pingpong_cooldown": 60},
+			deltas: []int{1, 1, 1},
+		},
+		{
+			name:   "detection is disabled",
+			params: map[string]int{"pingpong_count": 0, "pingpong_cooldown": 60},
+			deltas: []int{1, -1, 1, -1},
+		},
+		{
+			name:   "Ocns of the failed cell are not counted",
+			params: map[string]int{"pingpong_count": 2, "pingpong_cooldown": 60},
+			deltas: []int{1, -1, 1},
+			failed: true,
+		},
+	}
+
+	ctx := context.Background()
+	cell, neighbor := testCell("a"), testNeighbor("b")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestPingPongHandler(t, test.params)
+			ocn := 0
+			before := time.Now()
+			for _, delta := range test.deltas {
+				snapshot := &Snapshot{
+					Ocns: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{cell: {neighbor: ocnStep(ocn)}},
+				}
+				ocn += delta
+				decision := &Decision{
+					Ocns:   map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{cell: {neighbor: ocnStep(ocn)}},
+					Errors: make(map[storage.IDs]error),
+				}
+				if test.failed {
+					decision.Errors[cell] = errors.NewInternal("failed to apply")
+				}
+				h.detectPingPong(ctx, h.getControlState(ctx, snapshot, false), decision)
+			}
+			assert.Equal(t, test.frozen, h.ocnStore.IsInnerElementFrozen(ctx, cell, neighbor))
+
+			entries, err := h.ocnStore.ListFrozenElements(ctx)
+			assert.NoError(t, err)
+			if !test.frozen {
+				assert.Empty(t, entries)
+				return
+			}
+			// the pair is frozen for the cooldown
+			assert.Len(t, entries, 1)
+			cooldown := time.Duration(test.params["pingpong_cooldown"]) * time.Second
+			assert.False(t, entries[0].Until.Before(before.Add(cooldown)))
+			assert.False(t, entries[0].Until.After(time.Now().Add(cooldown)))
+		})
+	}
+}
+
+func TestHoldFrozenPairs(t *testing.T) {
+	ctx := context.Background()
+	cell := testCell("a")
+	tests := []struct {
+		name     string
+		until    time.Duration
+		expected meastype.QOffsetRange
+	}{
+		{name: "frozen pair keeps its Ocn", until: time.Hour, expected: ocnStep(0)},
+		{name: "pair after the cooldown moves", until: -time.Second, expected: ocnStep(3)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestPingPongHandler(t, nil)
+			assert.NoError(t, h.ocnStore.FreezeInnerElement(ctx, cell, testNeighbor("b"), time.Now().Add(test.until)))
+			snapshot := newTestSnapshot(map[string]int{"a": 10, "b": 10, "c": 10}, nil, ocnStep(0), Parameters{})
+			decision := &Decision{
+				Ocns: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+					cell: {testNeighbor("b"): ocnStep(3), testNeighbor("c"): ocnStep(3)},
+				},
+			}
+			h.holdFrozenPairs(ctx, h.getControlState(ctx, snapshot, false), decision)
+			assert.Equal(t, test.expected, decision.Ocns[cell][testNeighbor("b")])
+			// the other pairs of the cell are not held
+			assert.Equal(t, ocnStep(3), decision.Ocns[cell][testNeighbor("c")])
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+// isShadowMode returns true if the controller should only propose Ocns without sending E2 policies
+func (h *handler) isShadowMode(ctx context.Context) bool {
+	shadowMode, err := h.paramStore.Get(ctx, "shadow_mode")
+	return err == nil && shadowMode != 0
+}
+
+// controlState is the state kept across control cycles to decide which cells and pairs are held:
+// the failure records of the cells, the ping-pong history and the frozen pairs of the Ocn store.
+// Shadow mode keeps its own state on the proposals, so that a proposal neither quarantines a cell
+// nor freezes a pair of the normal mode.
+type controlState struct {
+	failureStore failurestorage.Store
+	pingPong     *pingPongDetector
+	ocnStore     ocnstorage.Store
+	// ocns are the current Ocns which the decision of this cycle changes
+	ocns map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+}
+
+// Ocn returns the current Ocn from the cell to the neighbor
+func (s *controlState) Ocn(ids storage.IDs, nIDs storage.IDs) (meastype.QOffsetRange, error) {
+	if ocn, ok := s.ocns[ids][nIDs]; ok {
+		return ocn, nil
+	}
+	return 0, errors.NewNotFound("element does not exist")
+}
+
+// getControlState returns the state of the normal mode, or that of shadow mode
+// whose current Ocns are the last proposals on top of the Ocns in the snapshot
+func (h *handler) getControlState(ctx context.Context, snapshot *Snapshot, shadow bool) *controlState {
+	if !shadow {
+		return &controlState{
+			failureStore: h.failureStore,
+			pingPong:     h.pingPong,
+			ocnStore:     h.ocnStore,
+			ocns:         snapshot.Ocns,
+		}
+	}
+	ocns := make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange, len(snapshot.Ocns))
+	for ids, m := range snapshot.Ocns {
+		ocns[ids] = make(map[storage.IDs]meastype.QOffsetRange, len(m))
+		for nIDs, ocn := range m {
+			ocns[ids][nIDs] = ocn
+		}
+	}
+	proposals, err := h.proposedOcnStore.ListElements(ctx)
+	if err != nil {
+		log.Error(err)
+	}
+	for _, e := range proposals {
+		if _, ok := ocns[e.Key]; !ok {
+			continue
+		}
+		for nIDs, ocn := range e.Value.Value {
+			if _, ok := ocns[e.Key][nIDs]; ok {
+				ocns[e.Key][nIDs] = ocn
+			}
+		}
+	}
+	return &controlState{
+		failureStore: h.shadowFailureStore,
+		pingPong:     h.shadowPingPong,
+		ocnStore:     h.proposedOcnStore,
+		ocns:         ocns,
+	}
+}
+
+// recordProposals saves the Ocns proposed in this cycle into the proposed Ocn store
+func (h *handler) recordProposals(ctx context.Context, decision *Decision) {
+	stale, err := h.proposedOcnStore.ListKeys(ctx, storage.WithFilter(func(ids storage.IDs, _ *ocnstorage.OcnMap) bool {
+		_, ok := decision.Ocns[ids]
+		return !ok
+	}))
+	if err != nil {
+		log.Error(err)
+	}
+	for _, ids := range stale {
+		err := h.proposedOcnStore.Delete(ctx, ids)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	for ids, ocns := range decision.Ocns {
+		proposal := make(map[storage.IDs]meastype.QOffsetRange)
+		for k, v := range ocns {
+			proposal[k] = v
+		}
+		_, err := h.proposedOcnStore.Put(ctx, ids, &ocnstorage.OcnMap{
+			Value: proposal,
+		})
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
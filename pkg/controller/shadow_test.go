@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadowControlState(t *testing.T) {
+	ctx := context.Background()
+	paramStore := paramstorage.NewStore()
+	for key, value := range map[string]int{"pingpong_count": 2, "quarantine_threshold": 1, "shadow_mode": 1} {
+		assert.NoError(t, paramStore.Put(ctx, key, value))
+	}
+	h := &handler{
+		ocnStore:           ocnstorage.NewStore(),
+		proposedOcnStore:   ocnstorage.NewStore(),
+		paramStore:         paramStore,
+		exclusionStore:     exclusionstorage.NewStore(),
+		failureStore:       failurestorage.NewStore(),
+		shadowFailureStore: failurestorage.NewStore(),
+		pingPong:           newPingPongDetector(),
+		shadowPingPong:     newPingPongDetector(),
+	}
+	// the Ocns are not applied in shadow mode, so the snapshot keeps the same Ocns in every cycle
+	snapshot := newTestSnapshot(map[string]int{"a": 10, "b": 10}, nil, ocnStep(0), Parameters{})
+	a, b := testCell("a"), testCell("b")
+
+	// cycle runs the steps of a control cycle in shadow mode after the algorithm proposes the Ocn from a to b
+	cycle := func(ocn meastype.QOffsetRange, failed bool) *Decision {
+		state := h.getControlState(ctx, snapshot, h.isShadowMode(ctx))
+		decision := &Decision{
+			Ocns:   map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{a: {testNeighbor("b"): ocn}},
+			Errors: make(map[storage.IDs]error),
+		}
+		if failed {
+			decision.Errors[b] = errors.NewInvalid("no num(UEs)")
+		}
+		h.removeQuarantinedCells(ctx, state, decision)
+		h.holdFrozenPairs(ctx, state, decision)
+		h.recordProposals(ctx, decision)
+		h.detectPingPong(ctx, state, decision)
+		h.recordFailures(ctx, state, snapshot, decision)
+		return decision
+	}
+
+	// the proposals go up and down against the last proposal, not against the Ocn applied
+	cycle(ocnStep(1), true)
+	cycle(ocnStep(0), false)
+	cycle(ocnStep(1), false)
+	assert.True(t, h.proposedOcnStore.IsInnerElementFrozen(ctx, a, testNeighbor("b")))
+	assert.False(t, h.ocnStore.IsInnerElementFrozen(ctx, a, testNeighbor("b")))
+
+	// the frozen pair keeps the last proposal in shadow mode
+	decision := cycle(ocnStep(3), false)
+	assert.Equal(t, ocnStep(1), decision.Ocns[a][testNeighbor("b")])
+
+	// the algorithm failure is recorded in the shadow state only
+	record, err := h.shadowFailureStore.Get(ctx, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, record.TotalFailures)
+	_, err = h.failureStore.Get(ctx, b)
+	assert.True(t, errors.IsNotFound(err))
+
+	// nothing of the shadow state holds the pairs of the normal mode
+	state := h.getControlState(ctx, snapshot, false)
+	assert.False(t, h.isOcnHeld(ctx, state, snapshot, a, testNeighbor("b")))
+	assert.NoError(t, h.shadowFailureStore.RecordSuccess(ctx, b))
+	_, err = h.shadowFailureStore.RecordFailure(ctx, b, errors.NewInvalid("no num(UEs)"), 1, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, h.isOcnHeld(ctx, h.getControlState(ctx, snapshot, true), snapshot, b, testNeighbor("a")))
+	assert.False(t, h.isOcnHeld(ctx, state, snapshot, b, testNeighbor("a")))
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// ThresholdAlgorithmName is the name of the threshold-based algorithm
+	ThresholdAlgorithmName = "threshold"
+)
+
+func init() {
+	RegisterAlgorithm(ThresholdAlgorithmName, NewThresholdAlgorithm)
+}
+
+// NewThresholdAlgorithm generates the threshold-based algorithm which steps Ocn by delta_ocn
+func NewThresholdAlgorithm() Algorithm {
+	return &thresholdAlgorithm{}
+}
+
+type thresholdAlgorithm struct{}
+
+func (a *thresholdAlgorithm) Name() string {
+	return ThresholdAlgorithmName
+}
+
+func (a *thresholdAlgorithm) Compute(_ context.Context, snapshot *Snapshot) (*Decision, error) {
+	decision := NewDecision()
+	for _, ids := range snapshot.Cells {
+		ocns, err := a.computeEachCell(ids, snapshot)
+		if err != nil {
+			decision.Errors[ids] = err
+			continue
+		}
+		if ocns != nil {
+			decision.Ocns[ids] = ocns
+		}
+	}
+	return decision, nil
+}
+
+func (a *thresholdAlgorithm) computeEachCell(ids storage.IDs, snapshot *Snapshot) (map[storage.IDs]meastype.QOffsetRange, error) {
+	targetThreshold := snapshot.TargetThreshold(ids)
+	overloadThreshold := snapshot.OverloadThreshold(ids)
+	ocnDeltaFactor := snapshot.Params.DeltaOcn
+
+	// calculate for each capacity and check sCell's and its neighbors' capacity
+	// if sCell load < target load threshold
+	// reduce Ocn
+	neighborList := snapshot.Neighbors[ids]
+	loadSCell, err := snapshot.Load(ids)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Serving cell (%v) load: %v / neighbor: %v / overload threshold %v, target threshold %v", ids, loadSCell, neighborList, overloadThreshold, targetThreshold)
+	if snapshot.IsUnderloaded(ids) && !snapshot.IsOverloaded(ids) {
+		tmpOcns := make(map[storage.IDs]meastype.QOffsetRange)
+		// send control message to reduce OCn for all neighbors
+		for _, nCellID := range neighborList {
+			ocn, err := snapshot.Ocn(ids, nCellID)
+			if err != nil {
+				return nil, err
+			}
+			if ocn-meastype.QOffsetRange(ocnDeltaFactor) < meastype.QOffsetMinus24dB {
+				ocn = meastype.QOffsetMinus24dB
+			} else {
+				ocn = ocn - meastype.QOffsetRange(ocnDeltaFactor)
+			}
+
+			tmpOcns[nCellID] = ocn
+		}
+		return tmpOcns, nil
+	}
+
+	// if sCell load > overload threshold && nCell < target load threshold
+	// increase Ocn
+	if snapshot.IsOverloaded(ids) {
+		tmpOcns := make(map[storage.IDs]meastype.QOffsetRange)
+		for _, nCellID := range neighborList {
+			ocn, err := snapshot.Ocn(ids, nCellID)
+			if err != nil {
+				return nil, err
+			}
+			tmpOcns[nCellID] = ocn
+			log.Debugf("Serving cell (%v)'s neighbor cell (%v) load: %v / overload threshold %v, target threshold %v", ids, nCellID, snapshot.loadOrZero(nCellID), snapshot.OverloadThreshold(nCellID), snapshot.TargetThreshold(nCellID))
+			if snapshot.IsUnderloaded(nCellID) {
+				if tmpOcns[nCellID]+meastype.QOffsetRange(ocnDeltaFactor) > meastype.QOffset24dB {
+					tmpOcns[nCellID] = meastype.QOffset24dB
+				} else {
+					tmpOcns[nCellID] = tmpOcns[nCellID] + meastype.QOffsetRange(ocnDeltaFactor)
+				}
+			}
+		}
+		return tmpOcns, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	triggerstorage "github.com/onosproject/onos-mlb/pkg/store/trigger"
+)
+
+const (
+	// DefaultTimeToTrigger is the default number of consecutive cycles a load condition should hold
+	DefaultTimeToTrigger = 1
+)
+
+// updateTriggers evaluates the overload and underload conditions of each cell with hysteresis and time-to-trigger
+// and puts the result into the snapshot and the trigger store
+//
+// A cell becomes overloaded when its load > overload threshold + overload hysteresis for time-to-trigger cycles
+// and it stays overloaded until its load <= overload threshold - overload hysteresis.
+// Likewise, a cell becomes underloaded when its load < target threshold - target hysteresis for time-to-trigger cycles
+// and it stays underloaded until its load >= target threshold + target hysteresis.
+// The thresholds are the effective thresholds of each cell after threshold overrides.
+func (h *handler) updateTriggers(ctx context.Context, snapshot *Snapshot) {
+	params := snapshot.Params
+	snapshot.Overloaded = make(map[storage.IDs]bool)
+	snapshot.Underloaded = make(map[storage.IDs]bool)
+	for _, cell := range snapshot.Cells {
+		load, err := snapshot.Load(cell)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		state, err := h.triggerStore.Get(ctx, cell)
+		if err != nil {
+			state = triggerstorage.State{
+				Key: cell,
+			}
+		}
+		state.Load = load
+		state.Updated = time.Now()
+		overloadThreshold := snapshot.OverloadThreshold(cell)
+		targetThreshold := snapshot.TargetThreshold(cell)
+		state.Overload.Update(load > overloadThreshold+params.OverloadHysteresis,
+			load <= overloadThreshold-params.OverloadHysteresis,
+			params.TimeToTrigger)
+		state.Underload.Update(load < targetThreshold-params.TargetHysteresis,
+			load >= targetThreshold+params.TargetHysteresis,
+			params.TimeToTrigger)
+		err = h.triggerStore.Put(ctx, cell, state)
+		if err != nil {
+			log.Error(err)
+		}
+
+		snapshot.Overloaded[cell] = state.Overload.Active
+		snapshot.Underloaded[cell] = state.Underload.Active
+		log.Debugf("Cell %v load: %v / overload trigger %+v, underload trigger %+v", cell, load, state.Overload, state.Underload)
+	}
+}
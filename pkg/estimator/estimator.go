@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package estimator
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+var log = logging.GetLogger()
+
+// Config maps each metric to its filter; a metric not in the config is not filtered
+type Config map[string]FilterConfig
+
+// Validate checks that the metrics are known and their filters are valid
+func (c Config) Validate() error {
+	for metric, f := range c {
+		switch metric {
+		case rnib.MetricNumUEs, rnib.MetricPRBDL, rnib.MetricPRBUL:
+		default:
+			return errors.NewInvalid("metric %s cannot be filtered", metric)
+		}
+		if err := f.Validate(); err != nil {
+			return errors.NewInvalid("filter of metric %s: %v", metric, err)
+		}
+	}
+	return nil
+}
+
+// Estimate is the raw and the filtered value of a cell's metric
+type Estimate struct {
+	Metric    string
+	IDs       storage.IDs
+	Filter    string
+	Raw       int
+	Filtered  float64
+	Timestamp time.Time
+}
+
+// NewEstimator generates the load estimator which filters the measurements of the cells
+func NewEstimator(config Config) Estimator {
+	return &estimator{
+		config: config,
+		states: make(map[stateKey]*state),
+	}
+}
+
+// Estimator filters the measurements from the monitor before the controller decides on them
+type Estimator interface {
+	// Estimate filters the measurement of the cell's metric and returns the filtered value rounded;
+	// the filter is updated only if the measurement is newer than the last one
+	Estimate(ctx context.Context, metric string, ids storage.IDs, measurement storage.Measurement) int
+
+	// Retain drops the filter states of the cells not in the list
+	Retain(ctx context.Context, cells []storage.IDs)
+
+	// List gets the last estimates of all cells
+	List(ctx context.Context) []Estimate
+
+	// GetConfig gets the filter of each metric
+	GetConfig(ctx context.Context) Config
+}
+
+type stateKey struct {
+	metric string
+	ids    storage.IDs
+}
+
+type state struct {
+	filter   filter
+	estimate Estimate
+}
+
+type estimator struct {
+	config Config
+	states map[stateKey]*state
+	mu     sync.Mutex
+}
+
+func (e *estimator) Estimate(_ context.Context, metric string, ids storage.IDs, measurement storage.Measurement) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.config[metric]
+	if !ok || c.Type == "" || c.Type == FilterNone {
+		return measurement.Value
+	}
+	key := stateKey{
+		metric: metric,
+		ids:    ids,
+	}
+	s, ok := e.states[key]
+	if !ok {
+		s = &state{
+			filter: newFilter(c),
+		}
+		e.states[key] = s
+	}
+	if !ok || measurement.Timestamp.After(s.estimate.Timestamp) {
+		s.estimate = Estimate{
+			Metric:    metric,
+			IDs:       ids,
+			Filter:    c.Type,
+			Raw:       measurement.Value,
+			Filtered:  s.filter.add(float64(measurement.Value)),
+			Timestamp: measurement.Timestamp,
+		}
+		log.Debugf("Filtered %s of cell %v: %v -> %v", metric, ids, measurement.Value, s.estimate.Filtered)
+	}
+	return int(math.Round(s.estimate.Filtered))
+}
+
+func (e *estimator) Retain(_ context.Context, cells []storage.IDs) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	keep := make(map[storage.IDs]bool)
+	for _, ids := range cells {
+		keep[ids] = true
+	}
+	for key := range e.states {
+		if !keep[key.ids] {
+			delete(e.states, key)
+		}
+	}
+}
+
+func (e *estimator) List(_ context.Context) []Estimate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := make([]Estimate, 0, len(e.states))
+	for _, s := range e.states {
+		result = append(result, s.estimate)
+	}
+	return result
+}
+
+func (e *estimator) GetConfig(_ context.Context) Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := make(Config)
+	for k, v := range e.config {
+		result[k] = v
+	}
+	return result
+}
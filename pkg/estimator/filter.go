@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package estimator
+
+import (
+	"sort"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const (
+	// FilterNone passes the raw value
+	FilterNone = "none"
+
+	// FilterEWMA is the exponentially weighted moving average
+	FilterEWMA = "ewma"
+
+	// FilterWindow is the mean of the last samples in a sliding window
+	FilterWindow = "window"
+
+	// FilterMedian is the median of the last samples in a sliding window
+	FilterMedian = "median"
+)
+
+// FilterConfig is the filter of a metric; alpha is the weight in percent of a new sample in the EWMA filter
+// and window is the number of samples in the window and median filters
+type FilterConfig struct {
+	Type   string `json:"type"`
+	Alpha  int    `json:"alpha,omitempty"`
+	Window int    `json:"window,omitempty"`
+}
+
+// Validate checks the filter type and its parameters
+func (c FilterConfig) Validate() error {
+	switch c.Type {
+	case "", FilterNone:
+	case FilterEWMA:
+		if c.Alpha <= 0 || c.Alpha > 100 {
+			return errors.NewInvalid("alpha of the ewma filter should be in (0, 100]")
+		}
+	case FilterWindow, FilterMedian:
+		if c.Window <= 0 {
+			return errors.NewInvalid("window of the %s filter should be positive", c.Type)
+		}
+	default:
+		return errors.NewInvalid("unknown filter %s", c.Type)
+	}
+	return nil
+}
+
+// filter keeps the state of a filter over the samples of a cell's metric
+type filter interface {
+	// add adds a new sample and returns the filtered value
+	add(sample float64) float64
+}
+
+func newFilter(c FilterConfig) filter {
+	switch c.Type {
+	case FilterEWMA:
+		return &ewmaFilter{
+			alpha: float64(c.Alpha) / 100,
+		}
+	case FilterWindow:
+		return &windowFilter{
+			size: c.Window,
+		}
+	case FilterMedian:
+		return &windowFilter{
+			size:   c.Window,
+			median: true,
+		}
+	}
+	return &noneFilter{}
+}
+
+type noneFilter struct{}
+
+func (f *noneFilter) add(sample float64) float64 {
+	return sample
+}
+
+type ewmaFilter struct {
+	alpha  float64
+	value  float64
+	primed bool
+}
+
+func (f *ewmaFilter) add(sample float64) float64 {
+	if !f.primed {
+		// the first sample has no history to average with
+		f.value = sample
+		f.primed = true
+		return f.value
+	}
+	f.value = f.alpha*sample + (1-f.alpha)*f.value
+	return f.value
+}
+
+type windowFilter struct {
+	size    int
+	median  bool
+	samples []float64
+}
+
+func (f *windowFilter) add(sample float64) float64 {
+	f.samples = append(f.samples, sample)
+	if len(f.samples) > f.size {
+		f.samples = f.samples[len(f.samples)-f.size:]
+	}
+	if f.median {
+		sorted := make([]float64, len(f.samples))
+		copy(sorted, f.samples)
+		sort.Float64s(sorted)
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[mid-1] + sorted[mid]) / 2
+		}
+		return sorted[mid]
+	}
+	sum := 0.0
+	for _, s := range f.samples {
+		sum += s
+	}
+	return sum / float64(len(f.samples))
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package estimator
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   FilterConfig
+		samples  []float64
+		expected []float64
+	}{
+		{
+			name:     "none",
+			config:   FilterConfig{Type: FilterNone},
+			samples:  []float64{1, 5, 3},
+			expected: []float64{1, 5, 3},
+		},
+		{
+			name:     "empty type passes the raw value",
+			config:   FilterConfig{},
+			samples:  []float64{1, 5, 3},
+			expected: []float64{1, 5, 3},
+		},
+		{
+			name:     "ewma",
+			config:   FilterConfig{Type: FilterEWMA, Alpha: 50},
+			samples:  []float64{10, 20, 20},
+			expected: []float64{10, 15, 17.5},
+		},
+		{
+			name:     "ewma with alpha 100 passes the raw value",
+			config:   FilterConfig{Type: FilterEWMA, Alpha: 100},
+			samples:  []float64{10, 20, 5},
+			expected: []float64{10, 20, 5},
+		},
+		{
+			name:     "window",
+			config:   FilterConfig{Type: FilterWindow, Window: 2},
+			samples:  []float64{10, 20, 30},
+			expected: []float64{10, 15, 25},
+		},
+		{
+			name:     "median",
+			config:   FilterConfig{Type: FilterMedian, Window: 3},
+			samples:  []float64{10, 50, 20, 30, 100},
+			expected: []float64{10, 30, 20, 30, 30},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NoError(t, test.config.Validate())
+			f := newFilter(test.config)
+			for i, sample := range test.samples {
+				assert.InDelta(t, test.expected[i], f.add(sample), 1e-9, "sample %d", i)
+			}
+		})
+	}
+}
+
+func TestFilterConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config FilterConfig
+		valid  bool
+	}{
+		{name: "none", config: FilterConfig{Type: FilterNone}, valid: true},
+		{name: "ewma", config: FilterConfig{Type: FilterEWMA, Alpha: 30}, valid: true},
+		{name: "ewma without alpha", config: FilterConfig{Type: FilterEWMA}},
+		{name: "ewma with alpha over 100", config: FilterConfig{Type: FilterEWMA, Alpha: 101}},
+		{name: "window", config: FilterConfig{Type: FilterWindow, Window: 5}, valid: true},
+		{name: "window without size", config: FilterConfig{Type: FilterWindow}},
+		{name: "median with negative size", config: FilterConfig{Type: FilterMedian, Window: -1}},
+		{name: "unknown", config: FilterConfig{Type: "kalman"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.IsInvalid(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package estimator
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// ForecastParams is the set of parameters of Holt's linear trend method;
+// alpha and beta are the smoothing factors of the level and the trend, and horizon is the number of cycles ahead
+type ForecastParams struct {
+	Alpha   float64
+	Beta    float64
+	Horizon int
+}
+
+// Forecast is the last forecast of a cell with the errors of the past forecasts;
+// the naive error is of the forecast which assumes the value does not change, to tell whether prediction helps
+type Forecast struct {
+	IDs         storage.IDs
+	Observation float64
+	Value       float64
+	Horizon     int
+	Samples     int
+	MAE         float64
+	RMSE        float64
+	NaiveMAE    float64
+}
+
+// NewForecaster generates the forecaster which predicts a value of each cell from its recent history
+func NewForecaster() Forecaster {
+	return &forecaster{
+		states: make(map[storage.IDs]*forecastState),
+	}
+}
+
+// Forecaster predicts a value of each cell with Holt's linear trend method and tracks its forecast errors
+type Forecaster interface {
+	// Forecast adds the observation of the cell in this cycle and returns the forecast the horizon cycles ahead
+	Forecast(ctx context.Context, ids storage.IDs, observation float64, params ForecastParams) float64
+
+	// Retain drops the forecast states of the cells not in the list
+	Retain(ctx context.Context, cells []storage.IDs)
+
+	// List gets the last forecasts of all cells
+	List(ctx context.Context) []Forecast
+}
+
+type pendingForecast struct {
+	value       float64
+	observation float64
+}
+
+type forecastState struct {
+	level   float64
+	trend   float64
+	pending []pendingForecast
+	absErr  float64
+	sqErr   float64
+	naive   float64
+	last    Forecast
+}
+
+type forecaster struct {
+	states map[storage.IDs]*forecastState
+	mu     sync.Mutex
+}
+
+func (f *forecaster) Forecast(_ context.Context, ids storage.IDs, observation float64, params ForecastParams) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.states[ids]
+	if !ok {
+		// the first observation has no trend
+		s = &forecastState{
+			level: observation,
+		}
+		f.states[ids] = s
+	} else {
+		level := params.Alpha*observation + (1-params.Alpha)*(s.level+s.trend)
+		s.trend = params.Beta*(level-s.level) + (1-params.Beta)*s.trend
+		s.level = level
+	}
+
+	if s.last.Horizon != params.Horizon {
+		// the pending forecasts and the errors are of another horizon
+		s.pending = nil
+		s.absErr = 0
+		s.sqErr = 0
+		s.naive = 0
+		s.last = Forecast{}
+	}
+	if len(s.pending) == params.Horizon && len(s.pending) > 0 {
+		p := s.pending[0]
+		s.pending = s.pending[1:]
+		s.absErr += math.Abs(observation - p.value)
+		s.sqErr += (observation - p.value) * (observation - p.value)
+		s.naive += math.Abs(observation - p.observation)
+		s.last.Samples++
+	}
+
+	value := s.level + float64(params.Horizon)*s.trend
+	s.pending = append(s.pending, pendingForecast{
+		value:       value,
+		observation: observation,
+	})
+
+	s.last.IDs = ids
+	s.last.Observation = observation
+	s.last.Value = value
+	s.last.Horizon = params.Horizon
+	if s.last.Samples > 0 {
+		n := float64(s.last.Samples)
+		s.last.MAE = s.absErr / n
+		s.last.RMSE = math.Sqrt(s.sqErr / n)
+		s.last.NaiveMAE = s.naive / n
+	}
+	return value
+}
+
+func (f *forecaster) Retain(_ context.Context, cells []storage.IDs) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keep := make(map[storage.IDs]bool)
+	for _, ids := range cells {
+		keep[ids] = true
+	}
+	for ids := range f.states {
+		if !keep[ids] {
+			delete(f.states, ids)
+		}
+	}
+}
+
+func (f *forecaster) List(_ context.Context) []Forecast {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]Forecast, 0, len(f.states))
+	for _, s := range f.states {
+		result = append(result, s.last)
+	}
+	return result
+}
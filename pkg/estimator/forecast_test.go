@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package estimator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecaster(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       ForecastParams
+		observations []float64
+		expected     []float64
+		last         Forecast
+	}{
+		{
+			name:         "constant series",
+			params:       ForecastParams{Alpha: 0.5, Beta: 0.5, Horizon: 2},
+			observations: []float64{10, 10, 10, 10},
+			expected:     []float64{10, 10, 10, 10},
+			last:         Forecast{Observation: 10, Value: 10, Horizon: 2, Samples: 2},
+		},
+		{
+			name:         "linear trend is followed without smoothing",
+			params:       ForecastParams{Alpha: 1, Beta: 1, Horizon: 1},
+			observations: []float64{10, 20, 30},
+			expected:     []float64{10, 30, 40},
+			last:         Forecast{Observation: 30, Value: 40, Horizon: 1, Samples: 2, MAE: 5, RMSE: math.Sqrt(50), NaiveMAE: 10},
+		},
+		{
+			name:         "trend is projected over the horizon",
+			params:       ForecastParams{Alpha: 1, Beta: 1, Horizon: 3},
+			observations: []float64{10, 20, 30},
+			expected:     []float64{10, 50, 60},
+			last:         Forecast{Observation: 30, Value: 60, Horizon: 3},
+		},
+		{
+			name:         "smoothing",
+			params:       ForecastParams{Alpha: 0.5, Beta: 0.5, Horizon: 1},
+			observations: []float64{10, 20},
+			// level = 0.5*20 + 0.5*10 = 15, trend = 0.5*(15-10) = 2.5
+			expected: []float64{10, 17.5},
+			last:     Forecast{Observation: 20, Value: 17.5, Horizon: 1, Samples: 1, MAE: 10, RMSE: 10, NaiveMAE: 10},
+		},
+	}
+
+	ids := storage.IDs{
+		NodeID: "e2:1",
+		PlmnID: "138426",
+		CellID: "a",
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewForecaster()
+			for i, observation := range test.observations {
+				assert.InDelta(t, test.expected[i], f.Forecast(context.Background(), ids, observation, test.params), 1e-9, "observation %d", i)
+			}
+			forecasts := f.List(context.Background())
+			assert.Len(t, forecasts, 1)
+			test.last.IDs = ids
+			assertForecast(t, test.last, forecasts[0])
+		})
+	}
+}
+
+func TestForecasterHorizonChange(t *testing.T) {
+	ids := storage.IDs{CellID: "a"}
+	f := NewForecaster()
+	for _, observation := range []float64{10, 20, 30} {
+		f.Forecast(context.Background(), ids, observation, ForecastParams{Alpha: 1, Beta: 1, Horizon: 1})
+	}
+	// the errors of the other horizon are dropped while the level and trend are kept
+	value := f.Forecast(context.Background(), ids, 40, ForecastParams{Alpha: 1, Beta: 1, Horizon: 2})
+	assert.InDelta(t, 60, value, 1e-9)
+	assertForecast(t, Forecast{IDs: ids, Observation: 40, Value: 60, Horizon: 2}, f.List(context.Background())[0])
+}
+
+func TestForecasterRetain(t *testing.T) {
+	a := storage.IDs{CellID: "a"}
+	b := storage.IDs{CellID: "b"}
+	f := NewForecaster()
+	params := ForecastParams{Alpha: 0.5, Beta: 0.5, Horizon: 1}
+	f.Forecast(context.Background(), a, 10, params)
+	f.Forecast(context.Background(), b, 20, params)
+
+	f.Retain(context.Background(), []storage.IDs{b})
+	forecasts := f.List(context.Background())
+	assert.Len(t, forecasts, 1)
+	assert.Equal(t, b, forecasts[0].IDs)
+}
+
+func assertForecast(t *testing.T, expected Forecast, actual Forecast) {
+	assert.Equal(t, expected.IDs, actual.IDs)
+	assert.InDelta(t, expected.Observation, actual.Observation, 1e-9)
+	assert.InDelta(t, expected.Value, actual.Value, 1e-9)
+	assert.Equal(t, expected.Horizon, actual.Horizon)
+	assert.Equal(t, expected.Samples, actual.Samples)
+	assert.InDelta(t, expected.MAE, actual.MAE, 1e-9)
+	assert.InDelta(t, expected.RMSE, actual.RMSE, 1e-9)
+	assert.InDelta(t, expected.NaiveMAE, actual.NaiveMAE, 1e-9)
+}
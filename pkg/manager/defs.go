@@ -21,6 +21,24 @@ const (
 	// MLBAppDefaultInterval is the default value of MLB controller interval
 	MLBAppDefaultInterval = 10
 
+	// MLBAppAlgorithmPath is the path to get the name of MLB load balancing algorithm
+	MLBAppAlgorithmPath = "/controller/algorithm"
+
+	// MLBAppExclusionsPath is the path to get the allow and deny lists of cells and neighbor relations
+	MLBAppExclusionsPath = "/controller/exclusions"
+
+	// MLBAppThresholdOverridesPath is the path to get the threshold profiles and their bindings
+	MLBAppThresholdOverridesPath = "/controller/thresholdOverrides"
+
+	// MLBAppCapacitiesPath is the path to get the capacities of cells
+	MLBAppCapacitiesPath = "/controller/capacities"
+
+	// MLBAppKPIMappingPath is the path to get the mapping from logical metrics to R-NIB KPI names
+	MLBAppKPIMappingPath = "/controller/kpiMapping"
+
+	// MLBAppSmoothingPath is the path to get the filter of each metric in the load estimator
+	MLBAppSmoothingPath = "/controller/smoothing"
+
 	// OCNDeltaFactor is the value how many inc/dec Ocn
 	OCNDeltaFactor = 3
 )
@@ -8,17 +8,26 @@ package manager
 import (
 	"context"
 	"github.com/onosproject/onos-mlb/pkg/southbound/e2policy"
+	"time"
 
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-lib-go/pkg/northbound"
 	"github.com/onosproject/onos-mlb/pkg/config"
 	"github.com/onosproject/onos-mlb/pkg/controller"
+	"github.com/onosproject/onos-mlb/pkg/estimator"
 	"github.com/onosproject/onos-mlb/pkg/monitor"
 	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
 	mlbnbi "github.com/onosproject/onos-mlb/pkg/northbound"
+	capacitystorage "github.com/onosproject/onos-mlb/pkg/store/capacity"
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
 	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
 	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
 	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	thresholdstorage "github.com/onosproject/onos-mlb/pkg/store/threshold"
+	triggerstorage "github.com/onosproject/onos-mlb/pkg/store/trigger"
+	"github.com/onosproject/onos-mlb/pkg/store/txn"
 )
 
 var log = logging.GetLogger()
@@ -35,6 +44,43 @@ type AppParameters struct {
 	RicActionID         int32
 	OverloadThreshold   int
 	TargetLoadThreshold int
+	Algorithm           string
+	MaxWorkers          int
+	NodeTimeout         int
+	QuarantineThreshold int
+	QuarantineBackoff   int
+	TargetHysteresis    int
+	OverloadHysteresis  int
+	TimeToTrigger       int
+	PingPongWindow      int
+	PingPongCount       int
+	PingPongCooldown    int
+	PIDKp               int
+	PIDKi               int
+	PIDKd               int
+	PIDIntegralLimit    int
+	PairMode            string
+	PairSumBound        int
+	GlobalMigrationRate int
+	ShadowMode          bool
+	LoadMetric          string
+	DefaultMaxUEs       int
+	MaxUEsPer100PRBs    int
+	LoadWeightUEs       int
+	LoadWeightPRBDL     int
+	LoadWeightPRBUL     int
+	StaleGracePeriod    int
+	MeasurementMaxAge   int
+	MeasurementTTL      int
+	HistoryRetention    int
+	HistoryMaxPoints    int
+	LoadSource          string
+	ForecastHorizon     int
+	ForecastAlpha       int
+	ForecastBeta        int
+	StoreDir            string
+	// SetFlags has the names of the flags given explicitly on the command line
+	SetFlags map[string]bool
 }
 
 // NewManager generates this application's manager
@@ -44,37 +90,183 @@ func NewManager(parameters AppParameters) *Manager {
 		log.Warn(err)
 	}
 	interval, err := appCfg.GetInterval(MLBAppIntervalPath)
+	intervalSet := err == nil
 	if err != nil {
 		log.Warn("set interval to default interval - reason: %v", err)
 		interval = MLBAppDefaultInterval
 	}
 
-	numUEsMeasStore := storage.NewStore()
-	neighborMeasStore := storage.NewStore()
+	algorithm, err := newAlgorithm(parameters.Algorithm, appCfg)
+	if err != nil {
+		log.Warnf("set algorithm to default algorithm %s - reason: %v", controller.DefaultAlgorithm, err)
+		algorithm, _ = controller.NewAlgorithm(controller.DefaultAlgorithm)
+	}
+	log.Infof("Load balancing algorithm: %s", algorithm.Name())
+
+	numUEsMeasStore := storage.NewStore[storage.IDs, storage.Measurement]()
+	neighborMeasStore := storage.NewStore[storage.IDs, []storage.IDs]()
+	numPRBsMeasStore := storage.NewStore[storage.IDs, storage.Measurement]()
+	prbUsedDLMeasStore := storage.NewStore[storage.IDs, storage.Measurement]()
+	prbUsedULMeasStore := storage.NewStore[storage.IDs, storage.Measurement]()
+	unmappedKPIStore := storage.NewStore[storage.IDs, rnib.UnmappedKPIs]()
 	ocnStore := ocnstorage.NewStore()
+	proposedOcnStore := ocnstorage.NewStore()
 	paramStore := paramstorage.NewStore()
-	err = paramStore.Put(context.Background(), "interval", interval)
-	if err != nil {
-		log.Error(err)
+	failureStore := failurestorage.NewStore()
+	shadowFailureStore := failurestorage.NewStore()
+	triggerStore := triggerstorage.NewStore()
+	exclusionStore := exclusionstorage.NewStore()
+	var exclusions exclusionstorage.Lists
+	if err := appCfg.GetObject(MLBAppExclusionsPath, &exclusions); err == nil {
+		err = exclusionStore.SetLists(context.Background(), exclusions)
+		if err != nil {
+			log.Error(err)
+		}
+	} else {
+		log.Debugf("no exclusion lists in config - reason: %v", err)
+	}
+	thresholdStore := thresholdstorage.NewStore()
+	var overrides thresholdstorage.Overrides
+	if err := appCfg.GetObject(MLBAppThresholdOverridesPath, &overrides); err == nil {
+		err = thresholdStore.SetOverrides(context.Background(), overrides)
+		if err != nil {
+			log.Error(err)
+		}
+	} else {
+		log.Debugf("no threshold overrides in config - reason: %v", err)
 	}
-	err = paramStore.Put(context.Background(), "delta_ocn", OCNDeltaFactor)
+	historyStore := historystorage.NewStore(time.Duration(parameters.HistoryRetention)*time.Second, parameters.HistoryMaxPoints)
+	capacityStore := capacitystorage.NewStore()
+	var capacities capacitystorage.Capacities
+	if err := appCfg.GetObject(MLBAppCapacitiesPath, &capacities); err == nil {
+		err = capacityStore.SetCapacities(context.Background(), capacities)
+		if err != nil {
+			log.Error(err)
+		}
+	} else {
+		log.Debugf("no cell capacities in config - reason: %v", err)
+	}
+	smoothing := make(estimator.Config)
+	if err := appCfg.GetObject(MLBAppSmoothingPath, &smoothing); err == nil {
+		if err = smoothing.Validate(); err != nil {
+			log.Warnf("do not filter measurements - reason: %v", err)
+			smoothing = make(estimator.Config)
+		}
+	} else {
+		log.Debugf("no smoothing in config - reason: %v", err)
+	}
+	loadEstimator := estimator.NewEstimator(smoothing)
+	forecaster := estimator.NewForecaster()
+	pairMode, err := controller.ParsePairMode(parameters.PairMode)
 	if err != nil {
-		log.Error(err)
+		log.Warnf("set pair mode to none - reason: %v", err)
 	}
-	err = paramStore.Put(context.Background(), "overload_threshold", parameters.OverloadThreshold)
+	loadMetric, err := controller.ParseLoadMetric(parameters.LoadMetric)
 	if err != nil {
-		log.Error(err)
+		log.Warnf("set load metric to share - reason: %v", err)
 	}
-	err = paramStore.Put(context.Background(), "target_threshold", parameters.TargetLoadThreshold)
+	loadSource, err := controller.ParseLoadSource(parameters.LoadSource)
 	if err != nil {
-		log.Error(err)
+		log.Warnf("set load source to current - reason: %v", err)
+	}
+	shadowMode := 0
+	if parameters.ShadowMode {
+		shadowMode = 1
+	}
+	defaults := []struct {
+		name  string
+		value int
+		set   bool // given explicitly as an argument or in config
+	}{
+		{"interval", interval, intervalSet},
+		{"delta_ocn", OCNDeltaFactor, false},
+		{"overload_threshold", parameters.OverloadThreshold, parameters.SetFlags["overloadThreshold"]},
+		{"target_threshold", parameters.TargetLoadThreshold, parameters.SetFlags["targetLoadThreshold"]},
+		{"max_workers", parameters.MaxWorkers, parameters.SetFlags["maxWorkers"]},
+		{"node_timeout", parameters.NodeTimeout, parameters.SetFlags["nodeTimeout"]},
+		{"target_hysteresis", parameters.TargetHysteresis, parameters.SetFlags["targetHysteresis"]},
+		{"overload_hysteresis", parameters.OverloadHysteresis, parameters.SetFlags["overloadHysteresis"]},
+		{"time_to_trigger", parameters.TimeToTrigger, parameters.SetFlags["timeToTrigger"]},
+		{"pingpong_window", parameters.PingPongWindow, parameters.SetFlags["pingPongWindow"]},
+		{"pingpong_count", parameters.PingPongCount, parameters.SetFlags["pingPongCount"]},
+		{"pingpong_cooldown", parameters.PingPongCooldown, parameters.SetFlags["pingPongCooldown"]},
+		{"pid_kp", parameters.PIDKp, parameters.SetFlags["pidKp"]},
+		{"pid_ki", parameters.PIDKi, parameters.SetFlags["pidKi"]},
+		{"pid_kd", parameters.PIDKd, parameters.SetFlags["pidKd"]},
+		{"pid_integral_limit", parameters.PIDIntegralLimit, parameters.SetFlags["pidIntegralLimit"]},
+		{"pair_mode", pairMode, parameters.SetFlags["pairMode"]},
+		{"pair_sum_bound", parameters.PairSumBound, parameters.SetFlags["pairSumBound"]},
+		{"global_migration_rate", parameters.GlobalMigrationRate, parameters.SetFlags["globalMigrationRate"]},
+		{"load_metric", loadMetric, parameters.SetFlags["loadMetric"]},
+		{"load_source", loadSource, parameters.SetFlags["loadSource"]},
+		{"forecast_horizon", parameters.ForecastHorizon, parameters.SetFlags["forecastHorizon"]},
+		{"forecast_alpha", parameters.ForecastAlpha, parameters.SetFlags["forecastAlpha"]},
+		{"forecast_beta", parameters.ForecastBeta, parameters.SetFlags["forecastBeta"]},
+		{"default_max_ues", parameters.DefaultMaxUEs, parameters.SetFlags["defaultMaxUEs"]},
+		{"max_ues_per_100prbs", parameters.MaxUEsPer100PRBs, parameters.SetFlags["maxUEsPer100PRBs"]},
+		{"load_weight_ues", parameters.LoadWeightUEs, parameters.SetFlags["loadWeightUEs"]},
+		{"load_weight_prb_dl", parameters.LoadWeightPRBDL, parameters.SetFlags["loadWeightPRBDL"]},
+		{"load_weight_prb_ul", parameters.LoadWeightPRBUL, parameters.SetFlags["loadWeightPRBUL"]},
+		{"shadow_mode", shadowMode, parameters.SetFlags["shadowMode"]},
+		{"stale_grace_period", parameters.StaleGracePeriod, parameters.SetFlags["staleGracePeriod"]},
+		{"measurement_max_age", parameters.MeasurementMaxAge, parameters.SetFlags["measurementMaxAge"]},
+		{"measurement_ttl", parameters.MeasurementTTL, parameters.SetFlags["measurementTTL"]},
+		{"quarantine_threshold", parameters.QuarantineThreshold, parameters.SetFlags["quarantineThreshold"]},
+		{"quarantine_backoff", parameters.QuarantineBackoff, parameters.SetFlags["quarantineBackoff"]},
+	}
+	explicit := make(map[string]bool)
+	for _, param := range defaults {
+		if err := paramStore.Put(context.Background(), param.name, param.value); err != nil {
+			log.Error(err)
+		}
+		if param.set {
+			explicit[param.name] = true
+		}
+	}
+
+	if parameters.StoreDir != "" {
+		persistentOcnStore, persistentParamStore, err := newPersistentStores(context.Background(), parameters.StoreDir, paramStore, explicit)
+		if err != nil {
+			log.Errorf("keep Ocns and parameters in memory - reason: %v", err)
+		} else {
+			ocnStore = persistentOcnStore
+			paramStore = persistentParamStore
+		}
 	}
 
-	rnibHandler, err := rnib.NewHandler()
+	kpiMapping := rnib.DefaultKPIMapping()
+	if err := appCfg.GetObject(MLBAppKPIMappingPath, &kpiMapping); err == nil {
+		if err = kpiMapping.Validate(); err != nil {
+			log.Warnf("set KPI mapping to default - reason: %v", err)
+			kpiMapping = rnib.DefaultKPIMapping()
+		}
+	} else {
+		log.Debugf("no KPI mapping in config - reason: %v", err)
+	}
+	log.Infof("KPI mapping: %v", kpiMapping)
+
+	rnibHandler, err := rnib.NewHandler(kpiMapping)
 	if err != nil {
 		log.Error(err)
 	}
-	monitorHandler := monitor.NewHandler(rnibHandler, numUEsMeasStore, neighborMeasStore, ocnStore)
+	// the monitor and the controller read and write the measurement and Ocn stores through this group
+	storeGroup := txn.NewGroup()
+	monitorHandler := monitor.NewHandler(rnibHandler, monitor.Stores{
+		NumUEs:        numUEsMeasStore,
+		Neighbors:     neighborMeasStore,
+		NumPRBs:       numPRBsMeasStore,
+		PRBUsedDL:     prbUsedDLMeasStore,
+		PRBUsedUL:     prbUsedULMeasStore,
+		UnmappedKPI:   unmappedKPIStore,
+		Ocn:           ocnStore,
+		ProposedOcn:   proposedOcnStore,
+		Params:        paramStore,
+		Failure:       failureStore,
+		ShadowFailure: shadowFailureStore,
+		Trigger:       triggerStore,
+		History:       historyStore,
+		Group:         storeGroup,
+	})
 
 	//e2ControlHandler := e2control.NewHandler(RcPreServiceModelName, RcPreServiceModelVersion,
 	//	AppID, parameters.E2tEndpoint)
@@ -82,7 +274,26 @@ func NewManager(parameters AppParameters) *Manager {
 	e2PolicyHandler := e2policy.NewHandler(RcPreServiceModelName, RcPreServiceModelVersion, AppID, parameters.E2tEndpoint, rnibHandler)
 
 	//ctrlHandler := controller.NewHandler(e2ControlHandler, monitorHandler, numUEsMeasStore, neighborMeasStore, ocnStore, paramStore)
-	ctrlHandler := controller.NewHandler(e2PolicyHandler, monitorHandler, numUEsMeasStore, neighborMeasStore, ocnStore, paramStore)
+	ctrlHandler := controller.NewHandler(e2PolicyHandler, monitorHandler, controller.Stores{
+		NumUEs:        numUEsMeasStore,
+		Neighbors:     neighborMeasStore,
+		NumPRBs:       numPRBsMeasStore,
+		PRBUsedDL:     prbUsedDLMeasStore,
+		PRBUsedUL:     prbUsedULMeasStore,
+		Ocn:           ocnStore,
+		ProposedOcn:   proposedOcnStore,
+		Params:        paramStore,
+		Failure:       failureStore,
+		ShadowFailure: shadowFailureStore,
+		Trigger:       triggerStore,
+		Exclusion:     exclusionStore,
+		Threshold:     thresholdStore,
+		Capacity:      capacityStore,
+		History:       historyStore,
+		Group:         storeGroup,
+		LoadEstimator: loadEstimator,
+		Forecaster:    forecaster,
+	}, algorithm)
 
 	return &Manager{
 		handlers: handlers{
@@ -93,10 +304,23 @@ func NewManager(parameters AppParameters) *Manager {
 			controllerHandler: ctrlHandler,
 		},
 		stores: stores{
-			numUEsMeasStore:   numUEsMeasStore,
-			neighborMeasStore: neighborMeasStore,
-			ocnStore:          ocnStore,
-			paramStore:        paramStore,
+			numUEsMeasStore:    numUEsMeasStore,
+			neighborMeasStore:  neighborMeasStore,
+			ocnStore:           ocnStore,
+			proposedOcnStore:   proposedOcnStore,
+			paramStore:         paramStore,
+			failureStore:       failureStore,
+			triggerStore:       triggerStore,
+			exclusionStore:     exclusionStore,
+			thresholdStore:     thresholdStore,
+			numPRBsMeasStore:   numPRBsMeasStore,
+			capacityStore:      capacityStore,
+			prbUsedDLMeasStore: prbUsedDLMeasStore,
+			prbUsedULMeasStore: prbUsedULMeasStore,
+			unmappedKPIStore:   unmappedKPIStore,
+			historyStore:       historyStore,
+			loadEstimator:      loadEstimator,
+			forecaster:         forecaster,
 		},
 		channels: channels{},
 		configs: configs{
@@ -106,6 +330,18 @@ func NewManager(parameters AppParameters) *Manager {
 	}
 }
 
+// newAlgorithm generates the algorithm given by the app parameter or by the app config
+func newAlgorithm(name string, appCfg config.Config) (controller.Algorithm, error) {
+	if name == "" {
+		var err error
+		name, err = appCfg.GetAlgorithm(MLBAppAlgorithmPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return controller.NewAlgorithm(name)
+}
+
 // Manager is a struct including this app's manager information and objects
 type Manager struct {
 	handlers handlers
@@ -123,10 +359,23 @@ type handlers struct {
 }
 
 type stores struct {
-	numUEsMeasStore   storage.Store
-	neighborMeasStore storage.Store
-	ocnStore          ocnstorage.Store
-	paramStore        paramstorage.Store
+	numUEsMeasStore    storage.MeasurementStore
+	neighborMeasStore  storage.NeighborStore
+	ocnStore           ocnstorage.Store
+	proposedOcnStore   ocnstorage.Store
+	paramStore         paramstorage.Store
+	failureStore       failurestorage.Store
+	triggerStore       triggerstorage.Store
+	exclusionStore     exclusionstorage.Store
+	thresholdStore     thresholdstorage.Store
+	numPRBsMeasStore   storage.MeasurementStore
+	capacityStore      capacitystorage.Store
+	prbUsedDLMeasStore storage.MeasurementStore
+	prbUsedULMeasStore storage.MeasurementStore
+	unmappedKPIStore   storage.Store[storage.IDs, rnib.UnmappedKPIs]
+	historyStore       historystorage.Store
+	loadEstimator      estimator.Estimator
+	forecaster         estimator.Forecaster
 }
 
 type channels struct {
@@ -143,6 +392,12 @@ func (m *Manager) Start() error {
 	if err != nil {
 		return err
 	}
+	if m.handlers.rnibHandler != nil {
+		err = m.handlers.rnibHandler.Run(context.Background())
+		if err != nil {
+			return err
+		}
+	}
 	err = m.handlers.controllerHandler.Run(context.Background())
 	return err
 }
@@ -157,7 +412,18 @@ func (m *Manager) startNorthboundServer() error {
 	s.AddService(mlbnbi.NewService(m.stores.numUEsMeasStore,
 		m.stores.neighborMeasStore,
 		m.stores.ocnStore,
-		m.stores.paramStore))
+		m.stores.proposedOcnStore,
+		m.stores.paramStore,
+		m.stores.failureStore,
+		m.stores.triggerStore,
+		m.stores.exclusionStore,
+		m.stores.thresholdStore,
+		m.stores.unmappedKPIStore,
+		m.stores.prbUsedDLMeasStore,
+		m.stores.prbUsedULMeasStore,
+		m.stores.historyStore,
+		m.stores.loadEstimator,
+		m.stores.forecaster))
 
 	doneCh := make(chan error)
 	go func() {
@@ -178,11 +444,11 @@ func (m *Manager) GetOcnStore() ocnstorage.Store {
 }
 
 // GetNumUEsStore returns NumUEsStore
-func (m *Manager) GetNumUEsStore() storage.Store {
+func (m *Manager) GetNumUEsStore() storage.MeasurementStore {
 	return m.stores.numUEsMeasStore
 }
 
 // GetNeighborStore returns neighbor store
-func (m *Manager) GetNeighborStore() storage.Store {
+func (m *Manager) GetNeighborStore() storage.NeighborStore {
 	return m.stores.neighborMeasStore
 }
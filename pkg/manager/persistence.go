@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/onosproject/onos-mlb/pkg/store/backend"
+	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+)
+
+const (
+	// OcnStoreFileName is the name of the file which persists Ocns in the store directory
+	OcnStoreFileName = "ocn.json"
+
+	// ParamStoreFileName is the name of the file which persists parameters in the store directory
+	ParamStoreFileName = "parameters.json"
+)
+
+// newPersistentStores opens the Ocn and parameter stores backed by files in the directory.
+// The persisted Ocns and parameters are reloaded so that the app keeps the state the RAN still applies;
+// the parameters given explicitly as arguments or in config override the persisted ones,
+// while the defaults of the others only fill in those which were not persisted.
+func newPersistentStores(ctx context.Context, dir string, params paramstorage.Store, explicit map[string]bool) (ocnstorage.Store, paramstorage.Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+	ocnBackend, err := backend.NewFileBackend(filepath.Join(dir, OcnStoreFileName))
+	if err != nil {
+		return nil, nil, err
+	}
+	ocnStore, err := ocnstorage.NewStoreWithBackend(ocnBackend)
+	if err != nil {
+		return nil, nil, err
+	}
+	paramBackend, err := backend.NewFileBackend(filepath.Join(dir, ParamStoreFileName))
+	if err != nil {
+		return nil, nil, err
+	}
+	paramStore, err := paramstorage.NewStoreWithBackend(paramBackend)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defaults, err := params.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range defaults {
+		persisted, err := paramStore.Get(ctx, key)
+		if err == nil && explicit[key] {
+			if persisted != value {
+				log.Infof("Parameter %s is %v from arguments or config instead of %v as persisted", key, value, persisted)
+			}
+		} else if err == nil {
+			if persisted != value {
+				log.Infof("Parameter %s is %v as persisted instead of %v", key, persisted, value)
+			}
+			continue
+		}
+		if err := paramStore.Put(ctx, key, value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ocnStore, paramStore, nil
+}
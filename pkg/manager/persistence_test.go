@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"testing"
+
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPersistentStores(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// each start puts the defaults from arguments and config into the in-memory store first
+	start := func(defaults map[string]int, explicit map[string]bool) paramstorage.Store {
+		params := paramstorage.NewStore()
+		for key, value := range defaults {
+			assert.NoError(t, params.Put(ctx, key, value))
+		}
+		_, paramStore, err := newPersistentStores(ctx, dir, params, explicit)
+		assert.NoError(t, err)
+		return paramStore
+	}
+
+	tests := []struct {
+		name     string
+		defaults map[string]int
+		explicit map[string]bool
+		update   map[string]int
+		expected map[string]int
+	}{
+		{
+			name:     "defaults are persisted on the first start",
+			defaults: map[string]int{"interval": 10, "overload_threshold": 100},
+			update:   map[string]int{"interval": 20},
+			expected: map[string]int{"interval": 10, "overload_threshold": 100},
+		},
+		{
+			name:     "persisted value wins over the default",
+			defaults: map[string]int{"interval": 10, "overload_threshold": 100},
+			expected: map[string]int{"interval": 20, "overload_threshold": 100},
+		},
+		{
+			name:     "explicit value wins over the persisted one",
+			defaults: map[string]int{"interval": 10, "overload_threshold": 80},
+			explicit: map[string]bool{"overload_threshold": true},
+			expected: map[string]int{"interval": 20, "overload_threshold": 80},
+		},
+		{
+			name:     "explicit value is persisted and new parameters are added",
+			defaults: map[string]int{"interval": 10, "overload_threshold": 100, "target_threshold": 30},
+			expected: map[string]int{"interval": 20, "overload_threshold": 80, "target_threshold": 30},
+		},
+	}
+
+	// the tests run in order on the same directory as consecutive restarts
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			paramStore := start(test.defaults, test.explicit)
+			params, err := paramStore.List(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, params)
+			// the change at runtime, e.g., through the NBI, is persisted for the next start
+			for key, value := range test.update {
+				assert.NoError(t, paramStore.Update(ctx, key, value))
+			}
+		})
+	}
+}
@@ -7,26 +7,70 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
 	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
 	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	triggerstorage "github.com/onosproject/onos-mlb/pkg/store/trigger"
+	"github.com/onosproject/onos-mlb/pkg/store/txn"
 )
 
 var log = logging.GetLogger()
 
 const (
 	WarnMsgRNIBEmpty = "R-NIB does not have enough information - either KPIMON monitoring result or neighbor information is missing"
+
+	// DefaultStaleGracePeriod is the default time in seconds a cell can be missing in R-NIB before it is removed
+	DefaultStaleGracePeriod = 60
+
+	// DefaultMeasurementTTL is the default time in seconds after which a measurement is evicted; 0 means no eviction
+	DefaultMeasurementTTL = 0
 )
 
+// Stores is the set of stores the monitor writes the R-NIB into
+type Stores struct {
+	NumUEs      storage.MeasurementStore
+	Neighbors   storage.NeighborStore
+	NumPRBs     storage.MeasurementStore
+	PRBUsedDL   storage.MeasurementStore
+	PRBUsedUL   storage.MeasurementStore
+	UnmappedKPI storage.Store[storage.IDs, rnib.UnmappedKPIs]
+	Ocn         ocnstorage.Store
+	ProposedOcn ocnstorage.Store
+	Params      paramstorage.Store
+	Failure     failurestorage.Store
+	// ShadowFailure has the failure records of the cells in shadow mode
+	ShadowFailure failurestorage.Store
+	Trigger       triggerstorage.Store
+	History       historystorage.Store
+	// Group coordinates the writes of the measurement and Ocn stores with the controller
+	Group *txn.Group
+}
+
 // NewHandler generates monitoring handler
-func NewHandler(rnibHandler rnib.Handler, numUEsMeasStore storage.Store, neighborMeasStore storage.Store, ocnStore ocnstorage.Store) Handler {
+func NewHandler(rnibHandler rnib.Handler, stores Stores) Handler {
 	return &handler{
-		rnibHandler:       rnibHandler,
-		numUEsMeasStore:   numUEsMeasStore,
-		neighborMeasStore: neighborMeasStore,
-		ocnStore:          ocnStore,
+		rnibHandler:        rnibHandler,
+		numUEsMeasStore:    stores.NumUEs,
+		neighborMeasStore:  stores.Neighbors,
+		numPRBsMeasStore:   stores.NumPRBs,
+		prbUsedDLMeasStore: stores.PRBUsedDL,
+		prbUsedULMeasStore: stores.PRBUsedUL,
+		unmappedKPIStore:   stores.UnmappedKPI,
+		ocnStore:           stores.Ocn,
+		proposedOcnStore:   stores.ProposedOcn,
+		paramStore:         stores.Params,
+		failureStore:       stores.Failure,
+		shadowFailureStore: stores.ShadowFailure,
+		triggerStore:       stores.Trigger,
+		historyStore:       stores.History,
+		storeGroup:         stores.Group,
+		missingSince:       make(map[storage.IDs]time.Time),
 	}
 }
 
@@ -37,10 +81,23 @@ type Handler interface {
 }
 
 type handler struct {
-	rnibHandler       rnib.Handler
-	numUEsMeasStore   storage.Store
-	neighborMeasStore storage.Store
-	ocnStore          ocnstorage.Store
+	rnibHandler        rnib.Handler
+	numUEsMeasStore    storage.MeasurementStore
+	neighborMeasStore  storage.NeighborStore
+	numPRBsMeasStore   storage.MeasurementStore
+	prbUsedDLMeasStore storage.MeasurementStore
+	prbUsedULMeasStore storage.MeasurementStore
+	unmappedKPIStore   storage.Store[storage.IDs, rnib.UnmappedKPIs]
+	ocnStore           ocnstorage.Store
+	proposedOcnStore   ocnstorage.Store
+	paramStore         paramstorage.Store
+	failureStore       failurestorage.Store
+	shadowFailureStore failurestorage.Store
+	triggerStore       triggerstorage.Store
+	historyStore       historystorage.Store
+	storeGroup         *txn.Group
+	// missingSince is the time since when each cell in the stores is missing in R-NIB
+	missingSince map[storage.IDs]time.Time
 }
 
 func (h *handler) Monitor(ctx context.Context) error {
@@ -48,12 +105,21 @@ func (h *handler) Monitor(ctx context.Context) error {
 	rnibList, err := h.rnibHandler.Get(ctx)
 	if err != nil {
 		return err
-	} else if len(rnibList) == 0 {
-		return fmt.Errorf(WarnMsgRNIBEmpty)
 	}
 
-	// store monitoring result
-	h.storeRNIB(ctx, rnibList)
+	// store monitoring result as a single update so that the controller does not see a half-stored result
+	_ = h.storeGroup.Update(func() error {
+		h.storeRNIB(ctx, rnibList)
+		h.removeStaleCells(ctx, rnibList)
+		h.evictExpiredMeasurements(ctx)
+		return nil
+	})
+	if err := h.ocnStore.Flush(ctx); err != nil {
+		log.Error(err)
+	}
+	if len(rnibList) == 0 {
+		return fmt.Errorf(WarnMsgRNIBEmpty)
+	}
 
 	log.Debugf("RNIB List %v", rnibList)
 
@@ -61,6 +127,7 @@ func (h *handler) Monitor(ctx context.Context) error {
 }
 
 func (h *handler) storeRNIB(ctx context.Context, rnibList []rnib.Element) {
+	unmapped := make(map[storage.IDs]bool)
 	for _, e := range rnibList {
 		key := storage.IDs{
 			NodeID:    e.Key.IDs.E2NodeID,
@@ -75,7 +142,30 @@ func (h *handler) storeRNIB(ctx context.Context, rnibList []rnib.Element) {
 				log.Error(err)
 			}
 		case rnib.NumUEs:
-			err := h.storeRNIBNumUEs(ctx, key, e.Value.(uint32))
+			err := h.storeRNIBMeasurement(ctx, h.numUEsMeasStore, key, e.Value.(uint32), e.Timestamp)
+			if err != nil {
+				log.Error(err)
+			}
+		case rnib.NumPRBs:
+			err := h.storeRNIBMeasurement(ctx, h.numPRBsMeasStore, key, e.Value.(uint32), e.Timestamp)
+			if err != nil {
+				log.Error(err)
+			}
+		case rnib.PRBUsedDL:
+			err := h.storeRNIBMeasurement(ctx, h.prbUsedDLMeasStore, key, e.Value.(uint32), e.Timestamp)
+			if err != nil {
+				log.Error(err)
+			}
+		case rnib.PRBUsedUL:
+			err := h.storeRNIBMeasurement(ctx, h.prbUsedULMeasStore, key, e.Value.(uint32), e.Timestamp)
+			if err != nil {
+				log.Error(err)
+			}
+		case rnib.Throughput:
+			log.Debugf("Throughput of cell %v: %v - not used for load yet", key, e.Value)
+		case rnib.Unmapped:
+			unmapped[key] = true
+			_, err := h.unmappedKPIStore.Put(ctx, key, e.Value.(rnib.UnmappedKPIs))
 			if err != nil {
 				log.Error(err)
 			}
@@ -83,6 +173,24 @@ func (h *handler) storeRNIB(ctx context.Context, rnibList []rnib.Element) {
 			log.Warnf("Unavailable aspects for this app - to be discarded: %v", e.Key.Aspect.String())
 		}
 	}
+	h.deleteMappedCells(ctx, unmapped)
+}
+
+// deleteMappedCells deletes the diagnostics of the cells which report the KPIs again
+func (h *handler) deleteMappedCells(ctx context.Context, unmapped map[storage.IDs]bool) {
+	mapped, err := h.unmappedKPIStore.ListKeys(ctx, storage.WithFilter(func(key storage.IDs, _ rnib.UnmappedKPIs) bool {
+		return !unmapped[key]
+	}))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, key := range mapped {
+		err := h.unmappedKPIStore.Delete(ctx, key)
+		if err != nil {
+			log.Error(err)
+		}
+	}
 }
 
 func (h *handler) storeRNIBNeighbors(ctx context.Context, key storage.IDs, neighborIDs []rnib.CellGlobalID) error {
@@ -98,10 +206,171 @@ func (h *handler) storeRNIBNeighbors(ctx context.Context, key storage.IDs, neigh
 	return err
 }
 
-func (h *handler) storeRNIBNumUEs(ctx context.Context, key storage.IDs, value uint32) error {
+func (h *handler) storeRNIBMeasurement(ctx context.Context, store storage.MeasurementStore, key storage.IDs, value uint32, timestamp time.Time) error {
 	measurement := storage.Measurement{
-		Value: int(value),
+		Value:     int(value),
+		Timestamp: timestamp,
 	}
-	_, err := h.numUEsMeasStore.Put(ctx, key, measurement)
+	_, err := store.Put(ctx, key, measurement)
 	return err
 }
+
+// removeStaleCells deletes the cells which have been missing in R-NIB for longer than the grace period
+// from the measurement stores and the Ocn store, so that they are neither counted nor controlled any longer
+func (h *handler) removeStaleCells(ctx context.Context, rnibList []rnib.Element) {
+	gracePeriod, err := h.paramStore.Get(ctx, "stale_grace_period")
+	if err != nil || gracePeriod < 0 {
+		gracePeriod = DefaultStaleGracePeriod
+	}
+
+	seen := make(map[storage.IDs]bool)
+	for _, e := range rnibList {
+		if e.Key.Aspect == rnib.Unmapped {
+			// the cell is in R-NIB but its measurements are not valid any longer
+			continue
+		}
+		seen[storage.IDs{
+			NodeID:    e.Key.IDs.E2NodeID,
+			PlmnID:    e.Key.IDs.CellGlobalID.PlmnID,
+			CellID:    e.Key.IDs.CellGlobalID.CellIdentity,
+			CellObjID: e.Key.IDs.CellObjectID,
+		}] = true
+	}
+
+	stored := make(map[storage.IDs]bool)
+	for _, store := range append(h.measStores(), cellStoreOf[*ocnstorage.OcnMap](h.ocnStore)) {
+		keys, err := store.ListCells(ctx)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		for _, key := range keys {
+			stored[key] = true
+		}
+	}
+
+	now := time.Now()
+	for key := range h.missingSince {
+		if !stored[key] || seen[key] {
+			delete(h.missingSince, key)
+		}
+	}
+	for key := range stored {
+		if seen[key] {
+			continue
+		}
+		since, ok := h.missingSince[key]
+		if !ok {
+			log.Warnf("Cell %v is missing in R-NIB - remove it after %v seconds unless it comes back", key, gracePeriod)
+			h.missingSince[key] = now
+			continue
+		}
+		if now.Sub(since) < time.Duration(gracePeriod)*time.Second {
+			continue
+		}
+		log.Infof("Cell %v has been missing in R-NIB since %v - remove it", key, since)
+		h.deleteCell(ctx, key)
+		delete(h.missingSince, key)
+	}
+}
+
+// deleteCell deletes the cell from every per-cell store and the cell from the Ocn maps of the other cells
+func (h *handler) deleteCell(ctx context.Context, key storage.IDs) {
+	for _, store := range h.measStores() {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Error(err)
+		}
+	}
+	for _, store := range []ocnstorage.Store{h.ocnStore, h.proposedOcnStore} {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Error(err)
+		}
+		deleteNeighbor(ctx, store, key)
+	}
+	if err := h.triggerStore.Delete(ctx, key); err != nil {
+		log.Error(err)
+	}
+	for _, store := range []failurestorage.Store{h.failureStore, h.shadowFailureStore} {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Error(err)
+		}
+	}
+	if err := h.historyStore.DeleteCell(ctx, key); err != nil {
+		log.Error(err)
+	}
+}
+
+// deleteNeighbor deletes the cell from the Ocn maps of the other cells;
+// neighbor IDs do not have E2 node ID, so the cell is matched with PLMN ID and cell ID
+func deleteNeighbor(ctx context.Context, store ocnstorage.Store, key storage.IDs) {
+	entries, err := store.ListAllInnerElement(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, e := range entries {
+		if e.Value.Key.PlmnID != key.PlmnID || e.Value.Key.CellID != key.CellID {
+			continue
+		}
+		if err := store.DeleteInnerElement(ctx, e.Key, e.Value.Key); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// cellStore is a store with cell keys whatever its values are
+type cellStore interface {
+	ListCells(ctx context.Context) ([]storage.IDs, error)
+	Delete(ctx context.Context, key storage.IDs) error
+}
+
+type anyCellStore[V any] struct {
+	storage.Store[storage.IDs, V]
+}
+
+func (s anyCellStore[V]) ListCells(ctx context.Context) ([]storage.IDs, error) {
+	return s.ListKeys(ctx)
+}
+
+func cellStoreOf[V any](store storage.Store[storage.IDs, V]) cellStore {
+	return anyCellStore[V]{
+		Store: store,
+	}
+}
+
+func (h *handler) measStores() []cellStore {
+	return []cellStore{
+		cellStoreOf[storage.Measurement](h.numUEsMeasStore),
+		cellStoreOf[[]storage.IDs](h.neighborMeasStore),
+		cellStoreOf[storage.Measurement](h.numPRBsMeasStore),
+		cellStoreOf[storage.Measurement](h.prbUsedDLMeasStore),
+		cellStoreOf[storage.Measurement](h.prbUsedULMeasStore),
+	}
+}
+
+// evictExpiredMeasurements deletes the measurements older than the TTL
+func (h *handler) evictExpiredMeasurements(ctx context.Context) {
+	ttl, err := h.paramStore.Get(ctx, "measurement_ttl")
+	if err != nil {
+		ttl = DefaultMeasurementTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	for _, store := range []storage.MeasurementStore{h.numUEsMeasStore, h.numPRBsMeasStore, h.prbUsedDLMeasStore, h.prbUsedULMeasStore} {
+		expired, err := store.ListElements(ctx, storage.WithFilter(func(_ storage.IDs, measurement storage.Measurement) bool {
+			return measurement.IsOlderThan(now, time.Duration(ttl)*time.Second)
+		}))
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		for _, e := range expired {
+			log.Infof("Measurement %v of cell %v expired - evict it", e.Value, e.Key)
+			if err := store.Delete(ctx, e.Key); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	"github.com/onosproject/onos-mlb/pkg/store/event"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
+	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	triggerstorage "github.com/onosproject/onos-mlb/pkg/store/trigger"
+	"github.com/onosproject/onos-mlb/pkg/store/txn"
+	"github.com/onosproject/onos-mlb/pkg/store/watcher"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPlmnID = "138426"
+
+func testCell(cellID string) storage.IDs {
+	return storage.IDs{
+		NodeID:    "e2:1",
+		PlmnID:    testPlmnID,
+		CellID:    cellID,
+		CellObjID: cellID,
+	}
+}
+
+func testNeighbor(cellID string) storage.IDs {
+	return storage.IDs{
+		PlmnID: testPlmnID,
+		CellID: cellID,
+	}
+}
+
+// testElement is the num(UEs) of the cell in R-NIB
+func testElement(cellID string) rnib.Element {
+	return rnib.Element{
+		Key: rnib.Key{
+			IDs: rnib.IDs{
+				E2NodeID:     "e2:1",
+				CellObjectID: cellID,
+				CellGlobalID: rnib.CellGlobalID{
+					PlmnID:       testPlmnID,
+					CellIdentity: cellID,
+				},
+			},
+			Aspect: rnib.NumUEs,
+		},
+		Value:     uint32(10),
+		Timestamp: time.Now(),
+	}
+}
+
+// newTestHandler generates the handler whose stores have cells a and b, which are neighbors of each other
+func newTestHandler(t *testing.T, gracePeriod int) *handler {
+	ctx := context.Background()
+	stores := Stores{
+		NumUEs:        storage.NewStore[storage.IDs, storage.Measurement](),
+		Neighbors:     storage.NewStore[storage.IDs, []storage.IDs](),
+		NumPRBs:       storage.NewStore[storage.IDs, storage.Measurement](),
+		PRBUsedDL:     storage.NewStore[storage.IDs, storage.Measurement](),
+		PRBUsedUL:     storage.NewStore[storage.IDs, storage.Measurement](),
+		UnmappedKPI:   storage.NewStore[storage.IDs, rnib.UnmappedKPIs](),
+		Ocn:           ocnstorage.NewStore(),
+		ProposedOcn:   ocnstorage.NewStore(),
+		Params:        paramstorage.NewStore(),
+		Failure:       failurestorage.NewStore(),
+		ShadowFailure: failurestorage.NewStore(),
+		Trigger:       triggerstorage.NewStore(),
+		History:       historystorage.NewStore(time.Hour, 10),
+		Group:         txn.NewGroup(),
+	}
+	assert.NoError(t, stores.Params.Put(ctx, "stale_grace_period", gracePeriod))
+	for cellID, nCellID := range map[string]string{"a": "b", "b": "a"} {
+		cell := testCell(cellID)
+		_, err := stores.NumUEs.Put(ctx, cell, storage.Measurement{Value: 10, Timestamp: time.Now()})
+		assert.NoError(t, err)
+		_, err = stores.Neighbors.Put(ctx, cell, []storage.IDs{testNeighbor(nCellID)})
+		assert.NoError(t, err)
+		for _, store := range []ocnstorage.Store{stores.Ocn, stores.ProposedOcn} {
+			_, err = store.Put(ctx, cell, &ocnstorage.OcnMap{
+				Value: map[storage.IDs]meastype.QOffsetRange{testNeighbor(nCellID): meastype.QOffset0dB},
+			})
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, stores.Trigger.Put(ctx, cell, triggerstorage.State{Key: cell, Load: 10}))
+		for _, store := range []failurestorage.Store{stores.Failure, stores.ShadowFailure} {
+			_, err = store.RecordFailure(ctx, cell, errors.NewTimeout("no ack"), 0, time.Minute)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, stores.History.Append(ctx, historystorage.Key{Metric: "numUEs", Cell: cell}, historystorage.Point{Time: time.Now(), Value: 10}))
+	}
+	return NewHandler(nil, stores).(*handler)
+}
+
+func TestRemoveStaleCells(t *testing.T) {
+	tests := []struct {
+		name       string
+		missingFor time.Duration
+		back       bool
+		removed    bool
+	}{
+		{
+			name:       "cell missing for less than the grace period is kept",
+			missingFor: 30 * time.Second,
+		},
+		{
+			name:       "cell coming back within the grace period is kept",
+			missingFor: 30 * time.Second,
+			back:       true,
+		},
+		{
+			name:       "cell missing for longer than the grace period is removed",
+			missingFor: 61 * time.Second,
+			removed:    true,
+		},
+	}
+
+	ctx := context.Background()
+	a, b := testCell("a"), testCell("b")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHandler(t, 60)
+			wctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			numUEsCh := make(chan event.Event, 10)
+			assert.NoError(t, h.numUEsMeasStore.Watch(wctx, numUEsCh, watcher.WithTypeFilter(storage.Deleted)))
+			ocnCh := make(chan event.Event, 10)
+			assert.NoError(t, h.ocnStore.Watch(wctx, ocnCh, watcher.WithTypeFilter(storage.Deleted)))
+
+			// the first cycle without cell b starts its grace period
+			h.removeStaleCells(ctx, []rnib.Element{testElement("a")})
+			assert.Contains(t, h.missingSince, b)
+			assert.NotContains(t, h.missingSince, a)
+
+			h.missingSince[b] = time.Now().Add(-test.missingFor)
+			rnibList := []rnib.Element{testElement("a")}
+			if test.back {
+				rnibList = append(rnibList, testElement("b"))
+			}
+			h.removeStaleCells(ctx, rnibList)
+			assert.Equal(t, !test.removed && !test.back, h.missingSince[b] != time.Time{})
+			assertCell(t, h, b, !test.removed)
+			// the other cell is kept but it does not have the removed cell as a neighbor any longer
+			assertCell(t, h, a, true)
+			ocns, err := h.ocnStore.Get(ctx, a)
+			assert.NoError(t, err)
+			_, ok := ocns.Value.Value[testNeighbor("b")]
+			assert.Equal(t, !test.removed, ok)
+			proposed, err := h.proposedOcnStore.Get(ctx, a)
+			assert.NoError(t, err)
+			_, ok = proposed.Value.Value[testNeighbor("b")]
+			assert.Equal(t, !test.removed, ok)
+
+			if !test.removed {
+				assert.Empty(t, numUEsCh)
+				assert.Empty(t, ocnCh)
+				return
+			}
+			for _, ch := range []chan event.Event{numUEsCh, ocnCh} {
+				select {
+				case e := <-ch:
+					assert.Equal(t, storage.Deleted, e.Type)
+					assert.Equal(t, b, e.Key)
+				case <-time.After(time.Second):
+					assert.Fail(t, "no Deleted event")
+				}
+			}
+		})
+	}
+}
+
+// assertCell checks whether the cell is in every per-cell store or in none of them
+func assertCell(t *testing.T, h *handler, ids storage.IDs, exists bool) {
+	ctx := context.Background()
+	_, err := h.numUEsMeasStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "num(UEs)")
+	_, err = h.neighborMeasStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "neighbors")
+	_, err = h.ocnStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "Ocn")
+	_, err = h.proposedOcnStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "proposed Ocn")
+	_, err = h.triggerStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "trigger")
+	_, err = h.failureStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "failure")
+	_, err = h.shadowFailureStore.Get(ctx, ids)
+	assert.Equal(t, exists, err == nil, "shadow failure")
+	keys, err := h.historyStore.ListKeys(ctx)
+	assert.NoError(t, err)
+	found := false
+	for _, key := range keys {
+		found = found || key.Cell == ids
+	}
+	assert.Equal(t, exists, found, "history")
+}
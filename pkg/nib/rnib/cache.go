@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rnib
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	topoapi "github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-ric-sdk-go/pkg/topo"
+)
+
+const (
+	// resyncInterval is the interval to list all E2 cells again in case the watch misses events
+	resyncInterval = 5 * time.Minute
+
+	// minReconnectBackoff is the first backoff before watching again after the watch stream is closed
+	minReconnectBackoff = time.Second
+
+	// maxReconnectBackoff is the largest backoff before watching again after the watch stream is closed
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// cache keeps the E2 cell objects in R-NIB up to date with topo watch events
+type cache struct {
+	objects map[topoapi.ID]cachedObject
+	synced  bool
+	mu      sync.RWMutex
+}
+
+// cachedObject is an R-NIB object with the time its revision was made; a listed object does not tell when it changed,
+// so a revision first seen in a list has the time it was listed, which is the latest time the revision may have been made
+type cachedObject struct {
+	object  topoapi.Object
+	revised time.Time
+}
+
+func newCache() *cache {
+	return &cache{
+		objects: make(map[topoapi.ID]cachedObject),
+	}
+}
+
+// list returns the cached objects; it returns false if the cache has not been synced with R-NIB yet
+func (c *cache) list() ([]cachedObject, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synced {
+		return nil, false
+	}
+	objects := make([]cachedObject, 0, len(c.objects))
+	for _, obj := range c.objects {
+		objects = append(objects, obj)
+	}
+	return objects, true
+}
+
+// replace replaces all cached objects with the objects listed from R-NIB
+func (c *cache) replace(objects []topoapi.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	cached := make(map[topoapi.ID]cachedObject)
+	for _, obj := range objects {
+		if !isE2Cell(obj) {
+			continue
+		}
+		revised := now
+		// keep the revision time of the objects which did not change
+		if prev, ok := c.objects[obj.GetID()]; ok && prev.object.GetRevision() == obj.GetRevision() {
+			revised = prev.revised
+		}
+		cached[obj.GetID()] = cachedObject{
+			object:  obj,
+			revised: revised,
+		}
+	}
+	c.objects = cached
+	c.synced = true
+}
+
+// update applies a topo watch event to the cache
+func (c *cache) update(event topoapi.Event) {
+	obj := event.GetObject()
+	if !isE2Cell(obj) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if event.GetType() == topoapi.EventType_REMOVED {
+		delete(c.objects, obj.GetID())
+		return
+	}
+	// the events received while listing may be older than the listed object
+	if cached, ok := c.objects[obj.GetID()]; ok && cached.object.GetRevision() >= obj.GetRevision() {
+		return
+	}
+	// the watch delivers an event as soon as the revision is made
+	c.objects[obj.GetID()] = cachedObject{
+		object:  obj,
+		revised: time.Now(),
+	}
+}
+
+func isE2Cell(obj topoapi.Object) bool {
+	return obj.GetEntity() != nil && obj.GetEntity().GetKindID() == topoapi.E2CELL
+}
+
+func (h *handler) Run(ctx context.Context) error {
+	go h.watch(ctx)
+	return nil
+}
+
+// watch watches E2 cells in R-NIB and resyncs the cache whenever it watches again after the stream is closed
+func (h *handler) watch(ctx context.Context) {
+	backoff := minReconnectBackoff
+	for {
+		ch := make(chan topoapi.Event)
+		err := h.rnibClient.Watch(ctx, ch, topo.WithWatchFilters(&topoapi.Filters{
+			KindFilter: &topoapi.Filter{
+				Filter: &topoapi.Filter_Equal_{
+					Equal_: &topoapi.EqualFilter{
+						Value: topoapi.E2CELL,
+					},
+				},
+			},
+		}), topo.WithNoReplay(true))
+		if err == nil {
+			// the watch is started before listing so that no change is missed
+			err = h.resync(ctx)
+			if err == nil {
+				backoff = minReconnectBackoff
+				h.consume(ctx, ch)
+			}
+		}
+		if err != nil {
+			log.Warnf("Failed to watch R-NIB - retry in %v: %v", backoff, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// consume applies the watch events to the cache until the stream is closed
+func (h *handler) consume(ctx context.Context, ch <-chan topoapi.Event) {
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				log.Warn("R-NIB watch stream is closed")
+				return
+			}
+			log.Debugf("R-NIB event: %v %v", event.GetType(), event.Object.GetID())
+			h.cache.update(event)
+		case <-ticker.C:
+			if err := h.resync(ctx); err != nil {
+				log.Warn(err)
+			}
+		case <-ctx.Done():
+			// drain the channel so that the watch goroutine can exit
+			go func() {
+				for range ch {
+				}
+			}()
+			return
+		}
+	}
+}
+
+// resync lists all objects in R-NIB and replaces the cache with them
+func (h *handler) resync(ctx context.Context) error {
+	objects, err := h.rnibClient.List(ctx)
+	if err != nil {
+		return err
+	}
+	h.cache.replace(objects)
+	log.Debugf("R-NIB cache is synced with %d objects", len(objects))
+	return nil
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rnib
+
+import (
+	"testing"
+	"time"
+
+	topoapi "github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/stretchr/testify/assert"
+)
+
+func testObject(id topoapi.ID, kind topoapi.ID, revision topoapi.Revision) topoapi.Object {
+	return topoapi.Object{
+		ID:       id,
+		Revision: revision,
+		Type:     topoapi.Object_ENTITY,
+		Obj: &topoapi.Object_Entity{
+			Entity: &topoapi.Entity{
+				KindID: kind,
+			},
+		},
+	}
+}
+
+func testEvent(eventType topoapi.EventType, obj topoapi.Object) topoapi.Event {
+	return topoapi.Event{
+		Type:   eventType,
+		Object: obj,
+	}
+}
+
+func TestCache(t *testing.T) {
+	// expected is the revision of each cached object and whether its revision time is set by the step
+	type expected struct {
+		revision topoapi.Revision
+		revised  bool
+	}
+	tests := []struct {
+		name     string
+		apply    func(c *cache)
+		expected map[topoapi.ID]expected
+	}{
+		{
+			name: "listed objects have the time they were listed",
+			apply: func(c *cache) {
+				c.replace([]topoapi.Object{
+					testObject("cell1", topoapi.E2CELL, 1),
+					testObject("cell2", topoapi.E2CELL, 1),
+					testObject("node1", topoapi.E2NODE, 1),
+				})
+			},
+			expected: map[topoapi.ID]expected{
+				"cell1": {revision: 1, revised: true},
+				"cell2": {revision: 1, revised: true},
+			},
+		},
+		{
+			name: "watched revision has its time",
+			apply: func(c *cache) {
+				c.update(testEvent(topoapi.EventType_UPDATED, testObject("cell1", topoapi.E2CELL, 2)))
+				c.update(testEvent(topoapi.EventType_ADDED, testObject("cell3", topoapi.E2CELL, 1)))
+			},
+			expected: map[topoapi.ID]expected{
+				"cell1": {revision: 2, revised: true},
+				"cell2": {revision: 1},
+				"cell3": {revision: 1, revised: true},
+			},
+		},
+		{
+			name: "older revision and other kinds are ignored",
+			apply: func(c *cache) {
+				c.update(testEvent(topoapi.EventType_UPDATED, testObject("cell1", topoapi.E2CELL, 1)))
+				c.update(testEvent(topoapi.EventType_ADDED, testObject("node1", topoapi.E2NODE, 1)))
+			},
+			expected: map[topoapi.ID]expected{
+				"cell1": {revision: 2},
+				"cell2": {revision: 1},
+				"cell3": {revision: 1},
+			},
+		},
+		{
+			name: "resync keeps the time of the unchanged revisions",
+			apply: func(c *cache) {
+				c.replace([]topoapi.Object{
+					testObject("cell1", topoapi.E2CELL, 2),
+					testObject("cell2", topoapi.E2CELL, 1),
+					testObject("cell3", topoapi.E2CELL, 2),
+				})
+			},
+			expected: map[topoapi.ID]expected{
+				"cell1": {revision: 2},
+				"cell2": {revision: 1},
+				"cell3": {revision: 2, revised: true},
+			},
+		},
+		{
+			name: "removed objects are deleted",
+			apply: func(c *cache) {
+				c.update(testEvent(topoapi.EventType_REMOVED, testObject("cell1", topoapi.E2CELL, 3)))
+				c.replace([]topoapi.Object{
+					testObject("cell2", topoapi.E2CELL, 1),
+				})
+			},
+			expected: map[topoapi.ID]expected{
+				"cell2": {revision: 1},
+			},
+		},
+	}
+
+	c := newCache()
+	_, ok := c.list()
+	assert.False(t, ok)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := time.Now()
+			test.apply(c)
+			objects, ok := c.list()
+			assert.True(t, ok)
+			assert.Len(t, objects, len(test.expected))
+			for _, obj := range objects {
+				e, ok := test.expected[obj.object.GetID()]
+				assert.True(t, ok, "unexpected object %v", obj.object.GetID())
+				assert.Equal(t, e.revision, obj.object.GetRevision(), "revision of %v", obj.object.GetID())
+				assert.Equal(t, e.revised, !obj.revised.Before(before), "revision time of %v", obj.object.GetID())
+			}
+		})
+	}
+}
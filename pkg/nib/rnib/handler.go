@@ -8,6 +8,10 @@ package rnib
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	topoapi "github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
@@ -18,33 +22,46 @@ import (
 var log = logging.GetLogger()
 
 const (
-	// AspectKeyNumUEsRANSim is the R-NIB aspect key of the number of UEs for RAN-Simulator
+	// AspectKeyNumUEsRANSim is the default R-NIB aspect key of the number of UEs for RAN-Simulator
 	AspectKeyNumUEsRANSim = "RRC.Conn.Avg"
 
-	// AspectKeyNumUEsOAI is the R-NIB aspect key of the number of UEs for OAI
+	// AspectKeyNumUEsOAI is the default R-NIB aspect key of the number of UEs for OAI
 	AspectKeyNumUEsOAI = "RRC.ConnMean"
+
+	// AspectKeyPRBUsedDL is the default R-NIB aspect key of the downlink PRB usage
+	AspectKeyPRBUsedDL = "RRU.PrbUsedDl"
+
+	// AspectKeyPRBUsedUL is the default R-NIB aspect key of the uplink PRB usage
+	AspectKeyPRBUsedUL = "RRU.PrbUsedUl"
 )
 
-// NewHandler generates the new RNIB handler
-func NewHandler() (Handler, error) {
+// NewHandler generates the new RNIB handler which maps KPIs to logical metrics with the KPI mapping
+func NewHandler(kpiMapping KPIMapping) (Handler, error) {
 	rnibClient, err := topo.NewClient()
 	if err != nil {
 		return nil, err
 	}
 	return &handler{
 		rnibClient: rnibClient,
+		kpiMapping: kpiMapping,
+		cache:      newCache(),
 	}, nil
 }
 
 // Handler includes RNIB handler's all functions
 type Handler interface {
-	// Get gets all RNIB
+	// Run starts to watch R-NIB and keep the cache of E2 cells up to date
+	Run(ctx context.Context) error
+
+	// Get gets all RNIB from the cache; it lists R-NIB until the cache is synced
 	Get(ctx context.Context) ([]Element, error)
 	GetE2NodeAspects(ctx context.Context, nodeID topoapi.ID) (*topoapi.E2Node, error)
 }
 
 type handler struct {
 	rnibClient topo.Client
+	kpiMapping KPIMapping
+	cache      *cache
 }
 
 func (h *handler) GetE2NodeAspects(ctx context.Context, nodeID topoapi.ID) (*topoapi.E2Node, error) {
@@ -59,24 +76,37 @@ func (h *handler) GetE2NodeAspects(ctx context.Context, nodeID topoapi.ID) (*top
 }
 
 func (h *handler) Get(ctx context.Context) ([]Element, error) {
-	objects, err := h.rnibClient.List(ctx)
-	if err != nil {
-		log.Error(err)
-		return nil, err
+	objects, ok := h.cache.list()
+	if !ok {
+		listed, err := h.rnibClient.List(ctx)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		// the listed objects do not tell when they changed, so they have the time they were listed
+		now := time.Now()
+		objects = make([]cachedObject, 0, len(listed))
+		for _, obj := range listed {
+			objects = append(objects, cachedObject{
+				object:  obj,
+				revised: now,
+			})
+		}
 	}
 
 	result := make([]Element, 0)
 
 	log.Debugf("R-NIB objects - %s", objects)
-	for _, obj := range objects {
-		if obj.GetEntity() == nil || obj.GetEntity().GetKindID() != topoapi.E2CELL {
+	for _, cached := range objects {
+		obj := cached.object
+		if !isE2Cell(obj) {
 			continue
 		}
 		log.Debugf("R-NIB each obj: %s", obj)
 		cellTopoID := obj.GetID()
 		e2NodeID, cellIdentity := idutils.ParseCellTopoID(string(cellTopoID))
 		cellObject := topoapi.E2Cell{}
-		err = obj.GetAspect(&cellObject)
+		err := obj.GetAspect(&cellObject)
 		if err != nil {
 			return nil, err
 		}
@@ -102,7 +132,7 @@ func (h *handler) Get(ctx context.Context) ([]Element, error) {
 			},
 		}
 
-		if len(cellObject.NeighborCellIDs) == 0 || len(cellObject.KpiReports) == 0 {
+		if len(cellObject.NeighborCellIDs) == 0 {
 			continue
 		}
 
@@ -116,29 +146,91 @@ func (h *handler) Get(ctx context.Context) ([]Element, error) {
 			plmnID = neighborCellID.PlmnID
 		}
 		ids.CellGlobalID.PlmnID = plmnID
+
+		// the measurements are as old as the KPI report, or as the revision of the cell object if the report time is unknown
+		timestamp, ok := h.kpiMapping.reportTime(cellObject.KpiReports)
+		if !ok {
+			timestamp = cached.revised
+		}
+
+		kpiValues, missing := h.kpiMapping.mapKPIs(cellObject.KpiReports)
+		if _, ok := kpiValues[NumUEs]; !ok {
+			// the cell cannot be controlled without the number of UEs; report it in diagnostics
+			reported := make([]string, 0, len(cellObject.KpiReports))
+			for kpiKey := range cellObject.KpiReports {
+				reported = append(reported, kpiKey)
+			}
+			sort.Strings(reported)
+			log.Warnf("Cell %v reports none of the KPIs for %v - reported KPIs: %v", ids, missing, reported)
+			result = append(result, Element{
+				Key: Key{
+					IDs:    ids,
+					Aspect: Unmapped,
+				},
+				Value: UnmappedKPIs{
+					MissingMetrics: missing,
+					ReportedKPIs:   reported,
+				},
+				Timestamp: timestamp,
+			})
+			continue
+		}
+
 		neighborElement := Element{
 			Key: Key{
 				IDs:    ids,
 				Aspect: Neighbors,
 			},
-			Value: neighbors,
+			Value:     neighbors,
+			Timestamp: timestamp,
 		}
 		result = append(result, neighborElement)
 
-		for kpiKey, kpiValue := range cellObject.KpiReports {
-			if kpiKey == AspectKeyNumUEsOAI || kpiKey == AspectKeyNumUEsRANSim {
-				kpiElement := Element{
-					Key: Key{
-						IDs:    ids,
-						Aspect: NumUEs,
-					},
-					Value: kpiValue,
-				}
-				result = append(result, kpiElement)
-				break
+		if numPRBs, ok := getNumPRBs(&cellObject); ok {
+			prbElement := Element{
+				Key: Key{
+					IDs:    ids,
+					Aspect: NumPRBs,
+				},
+				Value:     numPRBs,
+				Timestamp: timestamp,
+			}
+			result = append(result, prbElement)
+		}
+
+		for aspect, kpiValue := range kpiValues {
+			kpiElement := Element{
+				Key: Key{
+					IDs:    ids,
+					Aspect: aspect,
+				},
+				Value:     kpiValue,
+				Timestamp: timestamp,
 			}
+			result = append(result, kpiElement)
 		}
 	}
 
 	return result, nil
 }
+
+// getNumPRBs gets the number of PRBs in the downlink transmission bandwidth of the cell
+func getNumPRBs(cellObject *topoapi.E2Cell) (uint32, bool) {
+	var bandwidth *topoapi.TransmissionBandwidth
+	switch info := cellObject.GetNrModeInfo().(type) {
+	case *topoapi.E2Cell_FddInfo:
+		bandwidth = info.FddInfo.GetDlTransmissionBandwidth()
+	case *topoapi.E2Cell_TddInfo:
+		bandwidth = info.TddInfo.GetTransmissionBandwidth()
+	}
+	if bandwidth == nil || bandwidth.GetNrb() == topoapi.Nrb_NRB_UNKNOWN {
+		return 0, false
+	}
+	// the enum name has the number of PRBs such as NRB_273
+	numPRBs, err := strconv.Atoi(strings.TrimPrefix(bandwidth.GetNrb().String(), "NRB_"))
+	if err != nil {
+		log.Warnf("unknown number of PRBs %v", bandwidth.GetNrb())
+		return 0, false
+	}
+	return uint32(numPRBs), true
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rnib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	topoapi "github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCellObject(t *testing.T, cellID string, revision topoapi.Revision, kpiReports map[string]uint32) topoapi.Object {
+	obj := testObject(topoapi.ID("e2:1/5153/"+cellID), topoapi.E2CELL, revision)
+	err := obj.SetAspect(&topoapi.E2Cell{
+		CellObjectID: cellID,
+		CellGlobalID: &topoapi.CellGlobalID{
+			Value: cellID,
+		},
+		KpiReports: kpiReports,
+		NeighborCellIDs: []*topoapi.NeighborCellID{
+			{
+				CellGlobalID: &topoapi.CellGlobalID{Value: "13842601454c002"},
+				PlmnID:       "138426",
+			},
+		},
+	})
+	assert.NoError(t, err)
+	return obj
+}
+
+func TestGetListedCell(t *testing.T) {
+	h := &handler{
+		kpiMapping: DefaultKPIMapping(),
+		cache:      newCache(),
+	}
+	before := time.Now()
+	// the cell is only listed and never changed since this app started
+	h.cache.replace([]topoapi.Object{
+		testCellObject(t, "13842601454c001", 1, map[string]uint32{AspectKeyNumUEsRANSim: 10}),
+	})
+
+	elements, err := h.Get(context.Background())
+	assert.NoError(t, err)
+	numUEs := make([]Element, 0)
+	for _, e := range elements {
+		if e.Key.Aspect == NumUEs {
+			numUEs = append(numUEs, e)
+		}
+	}
+	assert.Len(t, numUEs, 1)
+	assert.Equal(t, uint32(10), numUEs[0].Value)
+	assert.False(t, numUEs[0].Timestamp.Before(before))
+}
+
+func TestGetReportTime(t *testing.T) {
+	mapping := DefaultKPIMapping()
+	mapping[MetricReportTime] = []KPIName{{Name: "report.time"}}
+	h := &handler{
+		kpiMapping: mapping,
+		cache:      newCache(),
+	}
+	h.cache.replace([]topoapi.Object{
+		testCellObject(t, "13842601454c001", 1, map[string]uint32{AspectKeyNumUEsRANSim: 10, "report.time": 1600000000}),
+	})
+
+	elements, err := h.Get(context.Background())
+	assert.NoError(t, err)
+	for _, e := range elements {
+		if e.Key.Aspect == NumUEs {
+			assert.Equal(t, time.Unix(1600000000, 0), e.Timestamp)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rnib
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const (
+	// MetricNumUEs is the logical metric of the number of UEs
+	MetricNumUEs = "numUEs"
+
+	// MetricPRBDL is the logical metric of the downlink PRB usage
+	MetricPRBDL = "prbDl"
+
+	// MetricPRBUL is the logical metric of the uplink PRB usage
+	MetricPRBUL = "prbUl"
+
+	// MetricThroughput is the logical metric of the throughput
+	MetricThroughput = "throughput"
+
+	// MetricReportTime is the logical metric of the time the KPIs were reported, in seconds since the Unix epoch
+	MetricReportTime = "reportTime"
+)
+
+var metricAspects = map[string]AspectType{
+	MetricNumUEs:     NumUEs,
+	MetricPRBDL:      PRBUsedDL,
+	MetricPRBUL:      PRBUsedUL,
+	MetricThroughput: Throughput,
+}
+
+// KPIName is a candidate KPI name in R-NIB with an optional scale factor applied to its value
+type KPIName struct {
+	Name  string  `json:"name"`
+	Scale float64 `json:"scale,omitempty"`
+}
+
+// KPIMapping maps each logical metric to the ordered list of candidate KPI names;
+// the first candidate a cell reports is used for the metric
+type KPIMapping map[string][]KPIName
+
+// DefaultKPIMapping returns the KPI mapping for RAN-Simulator and OAI
+func DefaultKPIMapping() KPIMapping {
+	return KPIMapping{
+		MetricNumUEs: {
+			{Name: AspectKeyNumUEsRANSim},
+			{Name: AspectKeyNumUEsOAI},
+		},
+		MetricPRBDL: {
+			{Name: AspectKeyPRBUsedDL},
+		},
+		MetricPRBUL: {
+			{Name: AspectKeyPRBUsedUL},
+		},
+	}
+}
+
+// Validate checks that each logical metric is known and each candidate has a name and a non-negative scale
+func (m KPIMapping) Validate() error {
+	for metric, names := range m {
+		if _, ok := metricAspects[metric]; !ok && metric != MetricReportTime {
+			return errors.NewInvalid("unknown logical metric %s", metric)
+		}
+		for _, n := range names {
+			if n.Name == "" {
+				return errors.NewInvalid("KPI name of logical metric %s is empty", metric)
+			}
+			if n.Scale < 0 {
+				return errors.NewInvalid("scale of KPI %s should not be negative", n.Name)
+			}
+		}
+	}
+	if len(m[MetricNumUEs]) == 0 {
+		return errors.NewInvalid("logical metric %s should have at least one KPI name", MetricNumUEs)
+	}
+	return nil
+}
+
+// UnmappedKPIs is the diagnostics of a cell which does not report the KPIs this app needs
+type UnmappedKPIs struct {
+	// MissingMetrics is the list of logical metrics for which the cell reports none of the candidate KPIs
+	MissingMetrics []string
+
+	// ReportedKPIs is the list of KPI names the cell reports
+	ReportedKPIs []string
+}
+
+// mapKPIs maps the KPI reports of a cell to the logical metrics;
+// it returns the value of each aspect found and the logical metrics not found
+func (m KPIMapping) mapKPIs(kpiReports map[string]uint32) (map[AspectType]uint32, []string) {
+	values := make(map[AspectType]uint32)
+	missing := make([]string, 0)
+	for metric, names := range m {
+		if _, ok := metricAspects[metric]; !ok {
+			continue
+		}
+		found := false
+		for _, n := range names {
+			value, ok := kpiReports[n.Name]
+			if !ok {
+				continue
+			}
+			if n.Scale > 0 {
+				value = uint32(math.Round(float64(value) * n.Scale))
+			}
+			values[metricAspects[metric]] = value
+			found = true
+			break
+		}
+		if !found {
+			missing = append(missing, metric)
+		}
+	}
+	sort.Strings(missing)
+	return values, missing
+}
+
+// reportTime gets the time the KPIs of a cell were reported; it returns false if the cell reports none of the candidates
+func (m KPIMapping) reportTime(kpiReports map[string]uint32) (time.Time, bool) {
+	for _, n := range m[MetricReportTime] {
+		if value, ok := kpiReports[n.Name]; ok && value > 0 {
+			return time.Unix(int64(value), 0), true
+		}
+	}
+	return time.Time{}, false
+}
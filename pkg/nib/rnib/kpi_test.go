@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rnib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapKPIs(t *testing.T) {
+	tests := []struct {
+		name       string
+		mapping    KPIMapping
+		kpiReports map[string]uint32
+		expected   map[AspectType]uint32
+		missing    []string
+	}{
+		{
+			name:       "RAN-Simulator names",
+			mapping:    DefaultKPIMapping(),
+			kpiReports: map[string]uint32{AspectKeyNumUEsRANSim: 10, AspectKeyPRBUsedDL: 20, AspectKeyPRBUsedUL: 30},
+			expected:   map[AspectType]uint32{NumUEs: 10, PRBUsedDL: 20, PRBUsedUL: 30},
+			missing:    []string{},
+		},
+		{
+			name:       "OAI name is the second candidate",
+			mapping:    DefaultKPIMapping(),
+			kpiReports: map[string]uint32{AspectKeyNumUEsOAI: 7, AspectKeyPRBUsedDL: 20, AspectKeyPRBUsedUL: 30},
+			expected:   map[AspectType]uint32{NumUEs: 7, PRBUsedDL: 20, PRBUsedUL: 30},
+			missing:    []string{},
+		},
+		{
+			name:       "first candidate wins",
+			mapping:    DefaultKPIMapping(),
+			kpiReports: map[string]uint32{AspectKeyNumUEsRANSim: 10, AspectKeyNumUEsOAI: 7},
+			expected:   map[AspectType]uint32{NumUEs: 10},
+			missing:    []string{MetricPRBDL, MetricPRBUL},
+		},
+		{
+			name: "scale factors",
+			mapping: KPIMapping{
+				MetricNumUEs:     {{Name: "ues"}},
+				MetricPRBDL:      {{Name: "prbDlRatio", Scale: 0.5}},
+				MetricThroughput: {{Name: "kbps", Scale: 0.001}},
+			},
+			kpiReports: map[string]uint32{"ues": 3, "prbDlRatio": 45, "kbps": 12345},
+			expected:   map[AspectType]uint32{NumUEs: 3, PRBUsedDL: 23, Throughput: 12},
+			missing:    []string{},
+		},
+		{
+			name:       "unknown KPIs are ignored",
+			mapping:    DefaultKPIMapping(),
+			kpiReports: map[string]uint32{"Vendor.Unknown": 1, AspectKeyNumUEsRANSim: 10},
+			expected:   map[AspectType]uint32{NumUEs: 10},
+			missing:    []string{MetricPRBDL, MetricPRBUL},
+		},
+		{
+			name:       "no KPI mapped",
+			mapping:    DefaultKPIMapping(),
+			kpiReports: map[string]uint32{"Vendor.Unknown": 1},
+			expected:   map[AspectType]uint32{},
+			missing:    []string{MetricNumUEs, MetricPRBDL, MetricPRBUL},
+		},
+		{
+			name: "report time is not an aspect",
+			mapping: KPIMapping{
+				MetricNumUEs:     {{Name: "ues"}},
+				MetricReportTime: {{Name: "time"}},
+			},
+			kpiReports: map[string]uint32{"ues": 3},
+			expected:   map[AspectType]uint32{NumUEs: 3},
+			missing:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, missing := test.mapping.mapKPIs(test.kpiReports)
+			assert.Equal(t, test.expected, values)
+			assert.Equal(t, test.missing, missing)
+		})
+	}
+}
+
+func TestReportTime(t *testing.T) {
+	mapping := KPIMapping{
+		MetricNumUEs:     {{Name: "ues"}},
+		MetricReportTime: {{Name: "time"}, {Name: "timeAlt"}},
+	}
+	tests := []struct {
+		name       string
+		kpiReports map[string]uint32
+		expected   time.Time
+		ok         bool
+	}{
+		{name: "first candidate", kpiReports: map[string]uint32{"time": 1000, "timeAlt": 2000}, expected: time.Unix(1000, 0), ok: true},
+		{name: "second candidate", kpiReports: map[string]uint32{"timeAlt": 2000}, expected: time.Unix(2000, 0), ok: true},
+		{name: "zero time is unknown", kpiReports: map[string]uint32{"time": 0}},
+		{name: "no report time", kpiReports: map[string]uint32{"ues": 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reportTime, ok := mapping.reportTime(test.kpiReports)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, reportTime)
+		})
+	}
+}
+
+func TestKPIMappingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping KPIMapping
+		valid   bool
+	}{
+		{name: "default", mapping: DefaultKPIMapping(), valid: true},
+		{
+			name: "all logical metrics",
+			mapping: KPIMapping{
+				MetricNumUEs:     {{Name: "ues", Scale: 1}},
+				MetricPRBDL:      {{Name: "dl"}},
+				MetricPRBUL:      {{Name: "ul"}},
+				MetricThroughput: {{Name: "kbps", Scale: 0.001}},
+				MetricReportTime: {{Name: "time"}},
+			},
+			valid: true,
+		},
+		{name: "unknown logical metric", mapping: KPIMapping{MetricNumUEs: {{Name: "ues"}}, "latency": {{Name: "ms"}}}},
+		{name: "empty KPI name", mapping: KPIMapping{MetricNumUEs: {{Name: ""}}}},
+		{name: "negative scale", mapping: KPIMapping{MetricNumUEs: {{Name: "ues", Scale: -1}}}},
+		{name: "no num(UEs)", mapping: KPIMapping{MetricPRBDL: {{Name: "dl"}}}},
+		{name: "num(UEs) without candidates", mapping: KPIMapping{MetricNumUEs: {}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.mapping.Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.IsInvalid(err))
+			}
+		})
+	}
+}
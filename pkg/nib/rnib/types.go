@@ -4,12 +4,18 @@
 
 package rnib
 
-import "github.com/onosproject/onos-api/go/onos/topo"
+import (
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/topo"
+)
 
 // Element is an element of R-NIB
 type Element struct {
 	Key   Key
 	Value interface{}
+	// Timestamp is the time the KPIs were reported, or the time the cell object in R-NIB was changed if the report time is unknown
+	Timestamp time.Time
 }
 
 // Key is a key of R-NIB
@@ -37,8 +43,13 @@ type AspectType int
 const (
 	Neighbors = iota
 	NumUEs
+	NumPRBs
+	PRBUsedDL
+	PRBUsedUL
+	Throughput
+	Unmapped
 )
 
 func (a AspectType) String() string {
-	return [...]string{"Neighbors", "NumUEs"}[a]
+	return [...]string{"Neighbors", "NumUEs", "NumPRBs", "PRBUsedDL", "PRBUsedUL", "Throughput", "Unmapped"}[a]
 }
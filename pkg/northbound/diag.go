@@ -0,0 +1,673 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package northbound
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/controller"
+	"github.com/onosproject/onos-mlb/pkg/estimator"
+	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
+	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	thresholdstorage "github.com/onosproject/onos-mlb/pkg/store/threshold"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// MlbDiagServiceName is the gRPC service name of the MLB diagnostics service
+	MlbDiagServiceName = "onos.mlb.MlbDiag"
+)
+
+// MlbDiagServer is the server API of the MLB diagnostics service.
+// It exposes the internal state of this app which the MLB API in onos-api does not include.
+// Requests and responses are generic protobuf structs so that onos-api does not have to be changed.
+type MlbDiagServer interface {
+	// GetCellFailures gets the failure records of the cells which failed to be controlled
+	GetCellFailures(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetCellTriggers gets the overload and underload trigger states of the cells
+	GetCellTriggers(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetFrozenOcns gets the serving and neighbor cell pairs whose Ocn is frozen after ping-pong
+	GetFrozenOcns(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetOcnViolations gets the neighbor pairs whose Ocns violate the pair mode
+	GetOcnViolations(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetProposedOcns gets the Ocns proposed in the last cycle next to the applied Ocns
+	GetProposedOcns(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// SetShadowMode switches between shadow mode and live mode
+	SetShadowMode(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetExclusions gets the allow and deny lists and the cells and relations excluded in the last cycle
+	GetExclusions(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// SetExclusions replaces the allow and deny lists
+	SetExclusions(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetThresholdOverrides gets the global parameters, the threshold overrides and the effective thresholds of each cell
+	GetThresholdOverrides(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// SetThresholdOverrides replaces the threshold profiles and their bindings
+	SetThresholdOverrides(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetUnmappedCells gets the cells which report none of the KPIs configured for the number of UEs
+	GetUnmappedCells(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetMeasurementAges gets the value, the timestamp and the staleness of the measurements of each cell
+	GetMeasurementAges(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetHistory gets the time series of loads and Ocns and the decisions of the past cycles
+	GetHistory(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetLoadEstimates gets the raw and the filtered measurements of each cell
+	GetLoadEstimates(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+	// GetForecasts gets the forecast number of UEs of each cell with the forecast errors
+	GetForecasts(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+}
+
+// RegisterMlbDiagServer registers the MLB diagnostics service to the gRPC server
+func RegisterMlbDiagServer(s *grpc.Server, srv MlbDiagServer) {
+	s.RegisterService(&mlbDiagServiceDesc, srv)
+}
+
+type mlbDiagMethod func(srv MlbDiagServer, ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+
+var mlbDiagServiceDesc = grpc.ServiceDesc{
+	ServiceName: MlbDiagServiceName,
+	HandlerType: (*MlbDiagServer)(nil),
+	Methods: []grpc.MethodDesc{
+		newMlbDiagMethodDesc("GetCellFailures", MlbDiagServer.GetCellFailures),
+		newMlbDiagMethodDesc("GetCellTriggers", MlbDiagServer.GetCellTriggers),
+		newMlbDiagMethodDesc("GetFrozenOcns", MlbDiagServer.GetFrozenOcns),
+		newMlbDiagMethodDesc("GetOcnViolations", MlbDiagServer.GetOcnViolations),
+		newMlbDiagMethodDesc("GetProposedOcns", MlbDiagServer.GetProposedOcns),
+		newMlbDiagMethodDesc("SetShadowMode", MlbDiagServer.SetShadowMode),
+		newMlbDiagMethodDesc("GetExclusions", MlbDiagServer.GetExclusions),
+		newMlbDiagMethodDesc("SetExclusions", MlbDiagServer.SetExclusions),
+		newMlbDiagMethodDesc("GetThresholdOverrides", MlbDiagServer.GetThresholdOverrides),
+		newMlbDiagMethodDesc("SetThresholdOverrides", MlbDiagServer.SetThresholdOverrides),
+		newMlbDiagMethodDesc("GetUnmappedCells", MlbDiagServer.GetUnmappedCells),
+		newMlbDiagMethodDesc("GetMeasurementAges", MlbDiagServer.GetMeasurementAges),
+		newMlbDiagMethodDesc("GetHistory", MlbDiagServer.GetHistory),
+		newMlbDiagMethodDesc("GetLoadEstimates", MlbDiagServer.GetLoadEstimates),
+		newMlbDiagMethodDesc("GetForecasts", MlbDiagServer.GetForecasts),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "onos-mlb/pkg/northbound/diag.go",
+}
+
+func newMlbDiagMethodDesc(name string, method mlbDiagMethod) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(structpb.Struct)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return method(srv.(MlbDiagServer), ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + MlbDiagServiceName + "/" + name,
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return method(srv.(MlbDiagServer), ctx, req.(*structpb.Struct))
+			}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
+
+// GetCellFailures gets the failure records of the cells which failed to be controlled
+func (s *Server) GetCellFailures(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	entries, err := s.failureStore.ListElements(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+
+	now := time.Now()
+	records := make(map[string]interface{})
+	for _, r := range entries {
+		records[idsToString(r.Key)] = map[string]interface{}{
+			"consecutive_failures": r.ConsecutiveFailures,
+			"total_failures":       r.TotalFailures,
+			"last_error":           r.LastError,
+			"last_failure":         r.LastFailure.Format(time.RFC3339),
+			"quarantined":          r.IsQuarantined(now),
+			"quarantined_until":    r.QuarantinedUntil.Format(time.RFC3339),
+		}
+	}
+
+	return structpb.NewStruct(records)
+}
+
+// GetCellTriggers gets the overload and underload trigger states of the cells
+func (s *Server) GetCellTriggers(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	entries, err := s.triggerStore.ListElements(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+
+	states := make(map[string]interface{})
+	for _, st := range entries {
+		states[idsToString(st.Key)] = map[string]interface{}{
+			"load":            st.Load,
+			"overloaded":      st.Overload.Active,
+			"overload_count":  st.Overload.Count,
+			"underloaded":     st.Underload.Active,
+			"underload_count": st.Underload.Count,
+			"updated":         st.Updated.Format(time.RFC3339),
+		}
+	}
+
+	return structpb.NewStruct(states)
+}
+
+// GetFrozenOcns gets the serving and neighbor cell pairs whose Ocn is frozen after ping-pong
+func (s *Server) GetFrozenOcns(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	entries, err := s.ocnStore.ListFrozenElements(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+
+	frozen := make(map[string]interface{})
+	for _, e := range entries {
+		key := idsToString(e.Key)
+		if _, ok := frozen[key]; !ok {
+			frozen[key] = make(map[string]interface{})
+		}
+		frozen[key].(map[string]interface{})[idsToString(e.InnerKey)] = e.Until.Format(time.RFC3339)
+	}
+
+	return structpb.NewStruct(frozen)
+}
+
+// GetOcnViolations gets the neighbor pairs whose Ocns violate the pair mode;
+// if the pair mode is none, it gets all pairs which are not antisymmetric
+func (s *Server) GetOcnViolations(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	pairMode, err := s.paramStore.Get(ctx, "pair_mode")
+	if err != nil {
+		pairMode = controller.PairModeNone
+	}
+	pairSumBound, err := s.paramStore.Get(ctx, "pair_sum_bound")
+	if err != nil {
+		pairSumBound = 0
+	}
+
+	entries, err := s.ocnStore.ListViolations(ctx, controller.PairSumBound(pairMode, pairSumBound))
+	if err != nil {
+		log.Debug(err)
+	}
+
+	violations := make([]interface{}, 0)
+	for _, v := range entries {
+		violations = append(violations, map[string]interface{}{
+			"cell":        idsToString(v.Key),
+			"neighbor":    idsToString(v.InnerKey),
+			"ocn":         int(v.Ocn),
+			"reverse_ocn": int(v.ReverseOcn),
+		})
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"violations": violations,
+	})
+}
+
+// GetProposedOcns gets the Ocns proposed in the last cycle next to the applied Ocns
+func (s *Server) GetProposedOcns(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	shadowMode, err := s.paramStore.Get(ctx, "shadow_mode")
+	if err != nil {
+		shadowMode = 0
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"shadow_mode": shadowMode != 0,
+		"proposed":    listOcns(ctx, s.proposedOcnStore),
+		"applied":     listOcns(ctx, s.ocnStore),
+	})
+}
+
+// SetShadowMode switches between shadow mode and live mode with the boolean field "enabled"
+func (s *Server) SetShadowMode(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	enabled, ok := request.GetFields()["enabled"]
+	if !ok {
+		return nil, errors.Status(errors.NewInvalid("field enabled is missing")).Err()
+	}
+	shadowMode := 0
+	if enabled.GetBoolValue() {
+		shadowMode = 1
+	}
+	err := s.paramStore.Put(ctx, "shadow_mode", shadowMode)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	log.Infof("Shadow mode: %v", enabled.GetBoolValue())
+	return structpb.NewStruct(map[string]interface{}{
+		"shadow_mode": enabled.GetBoolValue(),
+	})
+}
+
+// GetExclusions gets the allow and deny lists and the cells and relations excluded in the last cycle
+func (s *Server) GetExclusions(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	lists, err := s.exclusionStore.GetLists(ctx)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	report, err := s.exclusionStore.GetReport(ctx)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	// convert the lists through JSON to get the same field names as the request of SetExclusions
+	listsMap, err := toMap(lists)
+	if err != nil {
+		return nil, errors.Status(errors.NewInternal(err.Error())).Err()
+	}
+
+	cells := make([]interface{}, 0)
+	for _, ids := range report.Cells {
+		cells = append(cells, idsToString(ids))
+	}
+	relations := make([]interface{}, 0)
+	for _, r := range report.Relations {
+		relations = append(relations, map[string]interface{}{
+			"cell":     idsToString(r.Cell),
+			"neighbor": idsToString(r.Neighbor),
+		})
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"lists":              listsMap,
+		"excluded_cells":     cells,
+		"excluded_relations": relations,
+	})
+}
+
+// SetExclusions replaces the allow and deny lists with the lists in the request,
+// which has the fields allow_cells, deny_cells, allow_relations and deny_relations
+func (s *Server) SetExclusions(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	bytes, err := request.MarshalJSON()
+	if err != nil {
+		return nil, errors.Status(errors.NewInvalid(err.Error())).Err()
+	}
+	var lists exclusionstorage.Lists
+	err = json.Unmarshal(bytes, &lists)
+	if err != nil {
+		return nil, errors.Status(errors.NewInvalid(err.Error())).Err()
+	}
+	err = s.exclusionStore.SetLists(ctx, lists)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	log.Infof("Exclusion lists: %+v", lists)
+	return request, nil
+}
+
+// GetThresholdOverrides gets the global parameters which GetMlbParams returns, the threshold overrides
+// and the effective thresholds of each cell with the scope they come from
+func (s *Server) GetThresholdOverrides(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	params, err := s.GetMlbParams(ctx, nil)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	overrides, err := s.thresholdStore.GetOverrides(ctx)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	overridesMap, err := toMap(overrides)
+	if err != nil {
+		return nil, errors.Status(errors.NewInternal(err.Error())).Err()
+	}
+
+	global := thresholdstorage.Profile{
+		TargetThreshold:   int(params.GetTargetThreshold()),
+		OverloadThreshold: int(params.GetOverloadThreshold()),
+	}
+	entries, err := s.numUEsMeasStore.ListKeys(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+	cells := make(map[string]interface{})
+	for _, ids := range entries {
+		profile, scope := s.thresholdStore.Resolve(ctx, ids, global)
+		cells[idsToString(ids)] = map[string]interface{}{
+			"target_threshold":   profile.TargetThreshold,
+			"overload_threshold": profile.OverloadThreshold,
+			"scope":              scope,
+		}
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"interval":           int(params.GetInterval()),
+		"target_threshold":   global.TargetThreshold,
+		"overload_threshold": global.OverloadThreshold,
+		"delta_ocn":          int(params.GetDeltaOcn()),
+		"overrides":          overridesMap,
+		"cells":              cells,
+	})
+}
+
+// SetThresholdOverrides replaces the threshold profiles and their bindings with the request,
+// which has the fields profiles, cells, nodes, classes and members
+func (s *Server) SetThresholdOverrides(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	bytes, err := request.MarshalJSON()
+	if err != nil {
+		return nil, errors.Status(errors.NewInvalid(err.Error())).Err()
+	}
+	var overrides thresholdstorage.Overrides
+	err = json.Unmarshal(bytes, &overrides)
+	if err != nil {
+		return nil, errors.Status(errors.NewInvalid(err.Error())).Err()
+	}
+	err = s.thresholdStore.SetOverrides(ctx, overrides)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	log.Infof("Threshold overrides: %+v", overrides)
+	return request, nil
+}
+
+// GetUnmappedCells gets the cells which report none of the KPIs configured for the number of UEs,
+// with the logical metrics missing and the KPI names the cells report
+func (s *Server) GetUnmappedCells(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	entries, err := s.unmappedKPIStore.ListElements(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+
+	cells := make(map[string]interface{})
+	for _, e := range entries {
+		unmapped := e.Value
+		missing := make([]interface{}, 0, len(unmapped.MissingMetrics))
+		for _, m := range unmapped.MissingMetrics {
+			missing = append(missing, m)
+		}
+		reported := make([]interface{}, 0, len(unmapped.ReportedKPIs))
+		for _, k := range unmapped.ReportedKPIs {
+			reported = append(reported, k)
+		}
+		cells[idsToString(e.Key)] = map[string]interface{}{
+			"missing_metrics": missing,
+			"reported_kpis":   reported,
+		}
+	}
+
+	return structpb.NewStruct(cells)
+}
+
+// GetMeasurementAges gets the value, the timestamp and the age in seconds of the num(UEs) and PRB usage measurements
+// of each cell; a measurement older than measurement_max_age is stale and the controller treats it as unknown
+func (s *Server) GetMeasurementAges(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	maxAge, err := s.paramStore.Get(ctx, "measurement_max_age")
+	if err != nil {
+		maxAge = controller.DefaultMeasurementMaxAge
+	}
+	now := time.Now()
+	cells := make(map[string]interface{})
+	for name, store := range map[string]storage.MeasurementStore{
+		"num_ues":     s.numUEsMeasStore,
+		"prb_used_dl": s.prbUsedDLMeasStore,
+		"prb_used_ul": s.prbUsedULMeasStore,
+	} {
+		entries, err := store.ListElements(ctx)
+		if err != nil {
+			log.Debug(err)
+		}
+		for _, e := range entries {
+			measurement := e.Value
+			key := idsToString(e.Key)
+			if _, ok := cells[key]; !ok {
+				cells[key] = make(map[string]interface{})
+			}
+			cells[key].(map[string]interface{})[name] = map[string]interface{}{
+				"value":     measurement.Value,
+				"timestamp": measurement.Timestamp.Format(time.RFC3339),
+				"age":       int(now.Sub(measurement.Timestamp).Seconds()),
+				"stale":     measurement.IsOlderThan(now, time.Duration(maxAge)*time.Second),
+			}
+		}
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"max_age": maxAge,
+		"cells":   cells,
+	})
+}
+
+// GetHistory gets the time series of loads and Ocns and the decisions made in the last "since" seconds;
+// the optional fields "metric" and "cell_id" filter the time series and a positive "step" in seconds averages
+// the points in each step
+func (s *Server) GetHistory(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	fields := request.GetFields()
+	metric := fields["metric"].GetStringValue()
+	if metric != "" && metric != historystorage.MetricLoad && metric != historystorage.MetricOcn {
+		return nil, errors.Status(errors.NewInvalid("unknown metric %s", metric)).Err()
+	}
+	cellID := fields["cell_id"].GetStringValue()
+	since := fields["since"].GetNumberValue()
+	step := fields["step"].GetNumberValue()
+	if since < 0 || step < 0 {
+		return nil, errors.Status(errors.NewInvalid("since and step should not be negative")).Err()
+	}
+
+	to := time.Now()
+	// without since, the whole retention is queried
+	from := time.Time{}
+	if since > 0 {
+		from = to.Add(-time.Duration(since * float64(time.Second)))
+	}
+
+	entries, err := s.historyStore.ListKeys(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+	keys := make([]historystorage.Key, 0)
+	for _, key := range entries {
+		if metric != "" && key.Metric != metric {
+			continue
+		}
+		if cellID != "" && key.Cell.CellID != cellID {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	series := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		points, err := s.historyStore.Query(ctx, key, from, to, time.Duration(step*float64(time.Second)))
+		if err != nil {
+			log.Debug(err)
+			continue
+		}
+		values := make([]interface{}, 0, len(points))
+		for _, p := range points {
+			values = append(values, map[string]interface{}{
+				"time":  p.Time.Format(time.RFC3339),
+				"value": p.Value,
+			})
+		}
+		entry := map[string]interface{}{
+			"metric": key.Metric,
+			"cell":   idsToString(key.Cell),
+			"points": values,
+		}
+		if key.Metric == historystorage.MetricOcn {
+			entry["neighbor"] = idsToString(key.Neighbor)
+		}
+		series = append(series, entry)
+	}
+
+	records, err := s.historyStore.QueryDecisions(ctx, from, to)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	decisions := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		ocns := make(map[string]interface{})
+		for ids, nOcns := range r.Ocns {
+			if cellID != "" && ids.CellID != cellID {
+				continue
+			}
+			m := make(map[string]interface{})
+			for nIDs, ocn := range nOcns {
+				m[idsToString(nIDs)] = int(ocn)
+			}
+			ocns[idsToString(ids)] = m
+		}
+		errs := make(map[string]interface{})
+		for ids, e := range r.Errors {
+			if cellID != "" && ids.CellID != cellID {
+				continue
+			}
+			errs[idsToString(ids)] = e
+		}
+		decisions = append(decisions, map[string]interface{}{
+			"time":      r.Time.Format(time.RFC3339),
+			"algorithm": r.Algorithm,
+			"shadow":    r.Shadow,
+			"ocns":      ocns,
+			"errors":    errs,
+		})
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"series":    series,
+		"decisions": decisions,
+	})
+}
+
+// GetLoadEstimates gets the filter of each metric and the raw and the filtered num(UEs) and PRB usage of each cell;
+// the filtered value of a metric without filter is the raw value
+func (s *Server) GetLoadEstimates(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	filtered := make(map[string]map[storage.IDs]estimator.Estimate)
+	for _, e := range s.loadEstimator.List(ctx) {
+		if _, ok := filtered[e.Metric]; !ok {
+			filtered[e.Metric] = make(map[storage.IDs]estimator.Estimate)
+		}
+		filtered[e.Metric][e.IDs] = e
+	}
+
+	cells := make(map[string]interface{})
+	for metric, store := range map[string]storage.MeasurementStore{
+		rnib.MetricNumUEs: s.numUEsMeasStore,
+		rnib.MetricPRBDL:  s.prbUsedDLMeasStore,
+		rnib.MetricPRBUL:  s.prbUsedULMeasStore,
+	} {
+		entries, err := store.ListElements(ctx)
+		if err != nil {
+			log.Debug(err)
+		}
+		for _, e := range entries {
+			measurement := e.Value
+			value := map[string]interface{}{
+				"raw":      measurement.Value,
+				"filtered": measurement.Value,
+			}
+			// the estimate is of the last measurement the controller decided on
+			if estimate, ok := filtered[metric][e.Key]; ok {
+				value["filtered"] = estimate.Filtered
+				value["filter"] = estimate.Filter
+			}
+			key := idsToString(e.Key)
+			if _, ok := cells[key]; !ok {
+				cells[key] = make(map[string]interface{})
+			}
+			cells[key].(map[string]interface{})[metric] = value
+		}
+	}
+
+	config := make(map[string]interface{})
+	for metric, c := range s.loadEstimator.GetConfig(ctx) {
+		m, err := toMap(c)
+		if err != nil {
+			return nil, errors.Status(errors.NewInternal(err.Error())).Err()
+		}
+		config[metric] = m
+	}
+
+	return structpb.NewStruct(map[string]interface{}{
+		"smoothing": config,
+		"cells":     cells,
+	})
+}
+
+// GetForecasts gets the load source and the forecast number of UEs of each cell with the mean absolute error
+// and the root mean square error of the past forecasts; the naive error is of the forecast
+// which assumes the number of UEs does not change, so prediction helps if the error is smaller than it
+func (s *Server) GetForecasts(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	loadSource, err := s.paramStore.Get(ctx, "load_source")
+	if err != nil {
+		loadSource = controller.LoadSourceCurrent
+	}
+	cells := make(map[string]interface{})
+	absErr := 0.0
+	naiveErr := 0.0
+	samples := 0
+	for _, f := range s.forecaster.List(ctx) {
+		cells[idsToString(f.IDs)] = map[string]interface{}{
+			"observation": f.Observation,
+			"forecast":    f.Value,
+			"horizon":     f.Horizon,
+			"samples":     f.Samples,
+			"mae":         f.MAE,
+			"rmse":        f.RMSE,
+			"naive_mae":   f.NaiveMAE,
+		}
+		absErr += f.MAE * float64(f.Samples)
+		naiveErr += f.NaiveMAE * float64(f.Samples)
+		samples += f.Samples
+	}
+	result := map[string]interface{}{
+		"forecast_enabled": loadSource == controller.LoadSourceForecast,
+		"samples":          samples,
+		"cells":            cells,
+	}
+	if samples > 0 {
+		result["mae"] = absErr / float64(samples)
+		result["naive_mae"] = naiveErr / float64(samples)
+	}
+
+	return structpb.NewStruct(result)
+}
+
+// toMap converts a struct with JSON tags into a map which structpb accepts
+func toMap(v interface{}) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	err = json.Unmarshal(bytes, &m)
+	return m, err
+}
+
+func listOcns(ctx context.Context, store ocnstorage.Store) map[string]interface{} {
+	entries, err := store.ListAllInnerElement(ctx)
+	if err != nil {
+		log.Debug(err)
+	}
+
+	ocns := make(map[string]interface{})
+	for _, e := range entries {
+		key := idsToString(e.Key)
+		if _, ok := ocns[key]; !ok {
+			ocns[key] = make(map[string]interface{})
+		}
+		ocns[key].(map[string]interface{})[idsToString(e.Value.Key)] = int(e.Value.Value)
+	}
+	return ocns
+}
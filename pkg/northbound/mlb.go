@@ -6,61 +6,130 @@ package northbound
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	mlbapi "github.com/onosproject/onos-api/go/onos/mlb"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-lib-go/pkg/logging/service"
+	"github.com/onosproject/onos-mlb/pkg/estimator"
+	"github.com/onosproject/onos-mlb/pkg/nib/rnib"
+	exclusionstorage "github.com/onosproject/onos-mlb/pkg/store/exclusion"
+	failurestorage "github.com/onosproject/onos-mlb/pkg/store/failure"
+	historystorage "github.com/onosproject/onos-mlb/pkg/store/history"
 	ocnstorage "github.com/onosproject/onos-mlb/pkg/store/ocn"
 	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
 	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	thresholdstorage "github.com/onosproject/onos-mlb/pkg/store/threshold"
+	triggerstorage "github.com/onosproject/onos-mlb/pkg/store/trigger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 var log = logging.GetLogger()
 
+// ThresholdOverridesTrailer is the trailer key of GetMlbParams which has the threshold overrides in JSON
+const ThresholdOverridesTrailer = "threshold-overrides"
+
 // NewService generates a new Service for NBI
-func NewService(numUEsMeasStore storage.Store,
-	neighborMeasStore storage.Store,
+func NewService(numUEsMeasStore storage.MeasurementStore,
+	neighborMeasStore storage.NeighborStore,
 	ocnStore ocnstorage.Store,
-	paramStore paramstorage.Store) service.Service {
+	proposedOcnStore ocnstorage.Store,
+	paramStore paramstorage.Store,
+	failureStore failurestorage.Store,
+	triggerStore triggerstorage.Store,
+	exclusionStore exclusionstorage.Store,
+	thresholdStore thresholdstorage.Store,
+	unmappedKPIStore storage.Store[storage.IDs, rnib.UnmappedKPIs],
+	prbUsedDLMeasStore storage.MeasurementStore,
+	prbUsedULMeasStore storage.MeasurementStore,
+	historyStore historystorage.Store,
+	loadEstimator estimator.Estimator,
+	forecaster estimator.Forecaster) service.Service {
 	return &Service{
-		numUEsMeasStore:   numUEsMeasStore,
-		neighborMeasStore: neighborMeasStore,
-		ocnStore:          ocnStore,
-		paramStore:        paramStore,
+		numUEsMeasStore:    numUEsMeasStore,
+		neighborMeasStore:  neighborMeasStore,
+		ocnStore:           ocnStore,
+		proposedOcnStore:   proposedOcnStore,
+		paramStore:         paramStore,
+		failureStore:       failureStore,
+		triggerStore:       triggerStore,
+		exclusionStore:     exclusionStore,
+		thresholdStore:     thresholdStore,
+		unmappedKPIStore:   unmappedKPIStore,
+		prbUsedDLMeasStore: prbUsedDLMeasStore,
+		prbUsedULMeasStore: prbUsedULMeasStore,
+		historyStore:       historyStore,
+		loadEstimator:      loadEstimator,
+		forecaster:         forecaster,
 	}
 }
 
 // Service is a struct including stores and service objects
 type Service struct {
 	service.Service
-	numUEsMeasStore   storage.Store
-	neighborMeasStore storage.Store
-	ocnStore          ocnstorage.Store
-	paramStore        paramstorage.Store
+	numUEsMeasStore    storage.MeasurementStore
+	neighborMeasStore  storage.NeighborStore
+	ocnStore           ocnstorage.Store
+	proposedOcnStore   ocnstorage.Store
+	paramStore         paramstorage.Store
+	failureStore       failurestorage.Store
+	triggerStore       triggerstorage.Store
+	exclusionStore     exclusionstorage.Store
+	thresholdStore     thresholdstorage.Store
+	unmappedKPIStore   storage.Store[storage.IDs, rnib.UnmappedKPIs]
+	prbUsedDLMeasStore storage.MeasurementStore
+	prbUsedULMeasStore storage.MeasurementStore
+	historyStore       historystorage.Store
+	loadEstimator      estimator.Estimator
+	forecaster         estimator.Forecaster
 }
 
 // Register registers gRPC server
 func (s Service) Register(r *grpc.Server) {
 	server := &Server{
-		numUEsMeasStore:   s.numUEsMeasStore,
-		neighborMeasStore: s.neighborMeasStore,
-		ocnStore:          s.ocnStore,
-		paramStore:        s.paramStore,
+		numUEsMeasStore:    s.numUEsMeasStore,
+		neighborMeasStore:  s.neighborMeasStore,
+		ocnStore:           s.ocnStore,
+		proposedOcnStore:   s.proposedOcnStore,
+		paramStore:         s.paramStore,
+		failureStore:       s.failureStore,
+		triggerStore:       s.triggerStore,
+		exclusionStore:     s.exclusionStore,
+		thresholdStore:     s.thresholdStore,
+		unmappedKPIStore:   s.unmappedKPIStore,
+		prbUsedDLMeasStore: s.prbUsedDLMeasStore,
+		prbUsedULMeasStore: s.prbUsedULMeasStore,
+		historyStore:       s.historyStore,
+		loadEstimator:      s.loadEstimator,
+		forecaster:         s.forecaster,
 	}
 	mlbapi.RegisterMlbServer(r, server)
+	RegisterMlbDiagServer(r, server)
 }
 
 // Server is a struct including stores being used for exposing metrics
 type Server struct {
-	numUEsMeasStore   storage.Store
-	neighborMeasStore storage.Store
-	ocnStore          ocnstorage.Store
-	paramStore        paramstorage.Store
+	numUEsMeasStore    storage.MeasurementStore
+	neighborMeasStore  storage.NeighborStore
+	ocnStore           ocnstorage.Store
+	proposedOcnStore   ocnstorage.Store
+	paramStore         paramstorage.Store
+	failureStore       failurestorage.Store
+	triggerStore       triggerstorage.Store
+	exclusionStore     exclusionstorage.Store
+	thresholdStore     thresholdstorage.Store
+	unmappedKPIStore   storage.Store[storage.IDs, rnib.UnmappedKPIs]
+	prbUsedDLMeasStore storage.MeasurementStore
+	prbUsedULMeasStore storage.MeasurementStore
+	historyStore       historystorage.Store
+	loadEstimator      estimator.Estimator
+	forecaster         estimator.Forecaster
 }
 
-// GetMlbParams gets mlb parameters
+// GetMlbParams gets mlb parameters; since the response in onos-api has fixed fields,
+// the threshold overrides are returned in JSON in the ThresholdOverridesTrailer trailer
 func (s *Server) GetMlbParams(ctx context.Context, _ *mlbapi.GetMlbParamRequest) (*mlbapi.GetMlbParamResponse, error) {
 
 	interval, err := s.paramStore.Get(ctx, "interval")
@@ -87,6 +156,19 @@ func (s *Server) GetMlbParams(ctx context.Context, _ *mlbapi.GetMlbParamRequest)
 		DeltaOcn:          int32(deltaOcn),
 	}
 
+	overrides, err := s.thresholdStore.GetOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, err
+	}
+	if err := grpc.SetTrailer(ctx, metadata.Pairs(ThresholdOverridesTrailer, string(bytes))); err != nil {
+		// there is no trailer unless it is called through gRPC
+		log.Debug(err)
+	}
+
 	return resp, nil
 }
 
@@ -124,26 +206,22 @@ func (s *Server) SetMlbParams(ctx context.Context, request *mlbapi.SetMlbParamRe
 
 // GetOcn gets Ocn map
 func (s *Server) GetOcn(ctx context.Context, _ *mlbapi.GetOcnRequest) (*mlbapi.GetOcnResponse, error) {
-	ch := make(chan ocnstorage.Entry)
-	go func(ch chan ocnstorage.Entry) {
-		err := s.ocnStore.ListAllInnerElement(ctx, ch)
-		if err != nil {
-			log.Warn(err)
-			close(ch)
-		}
-	}(ch)
+	entries, err := s.ocnStore.ListAllInnerElement(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
 
 	mapOcnResp := make(map[string]*mlbapi.OcnRecord)
 
 	// Init map in ocnresp message
-	for e := range ch {
-		key := fmt.Sprintf("%s:%s:%s:%s", e.Key.NodeID, e.Key.PlmnID, e.Key.CellID, e.Key.CellObjID)
+	for _, e := range entries {
+		key := idsToString(e.Key)
 		if _, ok := mapOcnResp[key]; !ok {
 			mapOcnResp[key] = &mlbapi.OcnRecord{
 				OcnRecord: make(map[string]int32),
 			}
 		}
-		innerKey := fmt.Sprintf("%s:%s:%s:%s", e.Value.Key.NodeID, e.Value.Key.PlmnID, e.Value.Key.CellID, e.Value.Key.CellObjID)
+		innerKey := idsToString(e.Value.Key)
 		value := e.Value.Value
 		mapOcnResp[key].OcnRecord[innerKey] = int32(value)
 	}
@@ -152,3 +230,7 @@ func (s *Server) GetOcn(ctx context.Context, _ *mlbapi.GetOcnRequest) (*mlbapi.G
 		OcnMap: mapOcnResp,
 	}, nil
 }
+
+func idsToString(ids storage.IDs) string {
+	return fmt.Sprintf("%s:%s:%s:%s", ids.NodeID, ids.PlmnID, ids.CellID, ids.CellObjID)
+}
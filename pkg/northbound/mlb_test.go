@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package northbound
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	paramstorage "github.com/onosproject/onos-mlb/pkg/store/parameters"
+	thresholdstorage "github.com/onosproject/onos-mlb/pkg/store/threshold"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testStream is the server stream of a unary call which keeps its trailer
+type testStream struct {
+	trailer metadata.MD
+}
+
+func (s *testStream) Method() string {
+	return "/onos.mlb.Mlb/GetMlbParams"
+}
+
+func (s *testStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *testStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *testStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestGetMlbParamsOverrides(t *testing.T) {
+	ctx := context.Background()
+	paramStore := paramstorage.NewStore()
+	for key, value := range map[string]int{"interval": 10, "overload_threshold": 70, "target_threshold": 30, "delta_ocn": 3} {
+		assert.NoError(t, paramStore.Put(ctx, key, value))
+	}
+	overrides := thresholdstorage.Overrides{
+		Profiles: map[string]thresholdstorage.Profile{"small": {TargetThreshold: 20, OverloadThreshold: 50}},
+		Nodes:    []thresholdstorage.NodeBinding{{NodeID: "e2:1", Profile: "small"}},
+	}
+	thresholdStore := thresholdstorage.NewStore()
+	assert.NoError(t, thresholdStore.SetOverrides(ctx, overrides))
+	s := &Server{
+		paramStore:     paramStore,
+		thresholdStore: thresholdStore,
+	}
+
+	stream := &testStream{}
+	resp, err := s.GetMlbParams(grpc.NewContextWithServerTransportStream(ctx, stream), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(70), resp.GetOverloadThreshold())
+	assert.Equal(t, int32(30), resp.GetTargetThreshold())
+
+	values := stream.trailer.Get(ThresholdOverridesTrailer)
+	assert.Len(t, values, 1)
+	var actual thresholdstorage.Overrides
+	assert.NoError(t, json.Unmarshal([]byte(values[0]), &actual))
+	assert.Equal(t, overrides, actual)
+
+	// without gRPC, only the trailer is left out
+	_, err = s.GetMlbParams(ctx, nil)
+	assert.NoError(t, err)
+}
@@ -53,7 +53,9 @@ func NewHandler(smName string, smVersion string, appID string, e2tEndpoint strin
 }
 
 type Handler interface {
-	SetPolicyForOcn(ctx context.Context, nodeID string, ocns map[storage.IDs]meastype.QOffsetRange) error
+	// SetPolicyForOcn sets the Ocn policies to the E2 node; the subscription lives as long as ctx,
+	// while the E2 node acknowledges the subscription within ackTimeout, if it is positive
+	SetPolicyForOcn(ctx context.Context, nodeID string, ocns map[storage.IDs]meastype.QOffsetRange, ackTimeout time.Duration) error
 }
 
 type handler struct {
@@ -63,7 +65,7 @@ type handler struct {
 	mu          sync.Mutex
 }
 
-func (h *handler) SetPolicyForOcn(ctx context.Context, nodeID string, ocns map[storage.IDs]meastype.QOffsetRange) error {
+func (h *handler) SetPolicyForOcn(ctx context.Context, nodeID string, ocns map[storage.IDs]meastype.QOffsetRange, ackTimeout time.Duration) error {
 	policyForOcns := make([]subscriptionutil.PolicyForOcn, 0)
 	for k, v := range ocns {
 		policyID, err := subscriptionutil.CreatePolicyID(k.CellID)
@@ -88,14 +90,14 @@ func (h *handler) SetPolicyForOcn(ctx context.Context, nodeID string, ocns map[s
 			Offset:   int(v),
 		})
 	}
-	err := h.createSubscription(ctx, nodeID, policyForOcns)
+	err := h.createSubscription(ctx, nodeID, policyForOcns, ackTimeout)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (h *handler) createSubscription(ctx context.Context, nodeID string, policies []subscriptionutil.PolicyForOcn) error {
+func (h *handler) createSubscription(ctx context.Context, nodeID string, policies []subscriptionutil.PolicyForOcn, ackTimeout time.Duration) error {
 	log.Infof("Creating subscription for E2 node with ID: %v, policies: %+v", nodeID, policies)
 
 	actions := make([]e2api.Action, 0)
@@ -133,7 +135,7 @@ func (h *handler) createSubscription(ctx context.Context, nodeID string, policie
 		},
 	}
 
-	channelID, err := node.Subscribe(ctx, subName, subSpec, ch)
+	channelID, err := h.subscribe(ctx, node, subName, subSpec, ch, ackTimeout)
 	if err != nil {
 		log.Warn(err)
 		return err
@@ -141,21 +143,62 @@ func (h *handler) createSubscription(ctx context.Context, nodeID string, policie
 	log.Infof("Subscribe: %s / %+v", subName, subSpec)
 	log.Debugf("Channel ID: %s", channelID)
 
+	// the old subscription is removed out of the lock so that a slow E2 node does not hold up the others
 	h.mu.Lock()
-	if oldSubName, ok := h.subMap[nodeID]; ok {
+	oldSubName, ok := h.subMap[nodeID]
+	h.subMap[nodeID] = subName
+	h.mu.Unlock()
+
+	if ok {
 		err = node.Unsubscribe(ctx, oldSubName)
 		if err != nil {
 			log.Warn(err)
 		}
-		log.Infof("Unsubscribe: %s", subName)
+		log.Infof("Unsubscribe: %s", oldSubName)
 	}
 
-	h.subMap[nodeID] = subName
-	h.mu.Unlock()
-
 	return nil
 }
 
+type subscribeResult struct {
+	channelID e2api.ChannelID
+	err       error
+}
+
+// subscribe subscribes with ctx, which the subscription stream is bound to, and waits for the ack at most ackTimeout;
+// the subscription acknowledged after the timeout is removed
+func (h *handler) subscribe(ctx context.Context, node e2client.Node, subName string, subSpec e2api.SubscriptionSpec, ch chan e2api.Indication, ackTimeout time.Duration) (e2api.ChannelID, error) {
+	if ackTimeout <= 0 {
+		return node.Subscribe(ctx, subName, subSpec, ch)
+	}
+
+	resultCh := make(chan subscribeResult, 1)
+	go func() {
+		channelID, err := node.Subscribe(ctx, subName, subSpec, ch)
+		resultCh <- subscribeResult{
+			channelID: channelID,
+			err:       err,
+		}
+	}()
+
+	timer := time.NewTimer(ackTimeout)
+	defer timer.Stop()
+	select {
+	case result := <-resultCh:
+		return result.channelID, result.err
+	case <-timer.C:
+		go func() {
+			if result := <-resultCh; result.err == nil {
+				if err := node.Unsubscribe(ctx, subName); err != nil {
+					log.Warn(err)
+				}
+				log.Infof("Unsubscribe: %s - acknowledged after the timeout", subName)
+			}
+		}()
+		return "", errors.NewTimeout("E2 node did not acknowledge subscription %s in %v", subName, ackTimeout)
+	}
+}
+
 func (h *handler) getRanFunction(serviceModelsInfo map[string]*topoapi.ServiceModelInfo) (*topoapi.RCRanFunction, error) {
 	for _, sm := range serviceModelsInfo {
 		smName := strings.ToLower(sm.Name)
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Backend is a key/value backend which keeps the elements of a store beyond the lifetime of the process
+type Backend interface {
+	// Put puts key and its encoded value
+	Put(key string, value []byte) error
+
+	// Delete deletes the element with key
+	Delete(key string) error
+
+	// Write puts and deletes several elements at once
+	Write(puts map[string][]byte, deletes []string) error
+
+	// List gets all elements in this backend
+	List() (map[string][]byte, error)
+}
+
+// NewFileBackend opens the file-backed embedded key/value backend; the file is created if it does not exist.
+// All elements are kept in memory and every Put, Delete and Write rewrites the file, which suits the small stores of this app.
+func NewFileBackend(path string) (Backend, error) {
+	b := &fileBackend{
+		path:     path,
+		elements: make(map[string]json.RawMessage),
+	}
+	bytes, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.NewInternal("failed to read %s: %v", path, err)
+	}
+	if len(bytes) > 0 {
+		if err := json.Unmarshal(bytes, &b.elements); err != nil {
+			return nil, errors.NewInvalid("failed to decode %s: %v", path, err)
+		}
+	}
+	return b, nil
+}
+
+type fileBackend struct {
+	path     string
+	elements map[string]json.RawMessage
+	mu       sync.Mutex
+}
+
+func (b *fileBackend) Put(key string, value []byte) error {
+	if !json.Valid(value) {
+		return errors.NewInvalid("value of %s is not JSON", key)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.elements[key] = value
+	return b.flush()
+}
+
+func (b *fileBackend) Delete(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.elements[key]; !ok {
+		return nil
+	}
+	delete(b.elements, key)
+	return b.flush()
+}
+
+func (b *fileBackend) Write(puts map[string][]byte, deletes []string) error {
+	for key, value := range puts {
+		if !json.Valid(value) {
+			return errors.NewInvalid("value of %s is not JSON", key)
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, value := range puts {
+		b.elements[key] = value
+	}
+	for _, key := range deletes {
+		delete(b.elements, key)
+	}
+	return b.flush()
+}
+
+func (b *fileBackend) List() (map[string][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result := make(map[string][]byte, len(b.elements))
+	for k, v := range b.elements {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// flush writes all elements into a temporary file and renames it so that a crash does not leave a partial file
+func (b *fileBackend) flush() error {
+	bytes, err := json.Marshal(b.elements)
+	if err != nil {
+		return errors.NewInternal(err.Error())
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(b.path), filepath.Base(b.path)+".tmp")
+	if err != nil {
+		return errors.NewInternal("failed to write %s: %v", b.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(bytes); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), b.path)
+	}
+	if err != nil {
+		return errors.NewInternal("failed to write %s: %v", b.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileBackendReload(t *testing.T) {
+	tests := []struct {
+		name     string
+		apply    func(b Backend) error
+		expected map[string]string
+	}{
+		{
+			name: "put",
+			apply: func(b Backend) error {
+				if err := b.Put("a", []byte(`1`)); err != nil {
+					return err
+				}
+				return b.Put("b", []byte(`{"x":2}`))
+			},
+			expected: map[string]string{"a": `1`, "b": `{"x":2}`},
+		},
+		{
+			name: "delete",
+			apply: func(b Backend) error {
+				if err := b.Put("a", []byte(`1`)); err != nil {
+					return err
+				}
+				if err := b.Delete("missing"); err != nil {
+					return err
+				}
+				return b.Delete("a")
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "write puts and deletes at once",
+			apply: func(b Backend) error {
+				if err := b.Put("a", []byte(`1`)); err != nil {
+					return err
+				}
+				return b.Write(map[string][]byte{"b": []byte(`2`), "c": []byte(`3`)}, []string{"a"})
+			},
+			expected: map[string]string{"b": `2`, "c": `3`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "store.json")
+			b, err := NewFileBackend(path)
+			assert.NoError(t, err)
+			assert.NoError(t, test.apply(b))
+
+			// a new backend of the same file reloads the elements
+			reloaded, err := NewFileBackend(path)
+			assert.NoError(t, err)
+			elements, err := reloaded.List()
+			assert.NoError(t, err)
+			actual := make(map[string]string)
+			for k, v := range elements {
+				actual[k] = string(v)
+			}
+			assert.Equal(t, test.expected, actual)
+
+			// no temporary file is left behind the renamed file
+			files, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, files, 1)
+		})
+	}
+}
+
+func TestFileBackendOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  *string
+		valid    bool
+		elements int
+	}{
+		{name: "missing file", valid: true},
+		{name: "empty file", content: stringPtr(""), valid: true},
+		{name: "file with elements", content: stringPtr(`{"a":1,"b":2}`), valid: true, elements: 2},
+		{name: "corrupt file", content: stringPtr(`{"a":`)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "store.json")
+			if test.content != nil {
+				assert.NoError(t, os.WriteFile(path, []byte(*test.content), 0644))
+			}
+			b, err := NewFileBackend(path)
+			if !test.valid {
+				assert.True(t, errors.IsInvalid(err))
+				return
+			}
+			assert.NoError(t, err)
+			elements, err := b.List()
+			assert.NoError(t, err)
+			assert.Len(t, elements, test.elements)
+		})
+	}
+}
+
+func TestFileBackendInvalidValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	b, err := NewFileBackend(path)
+	assert.NoError(t, err)
+	assert.True(t, errors.IsInvalid(b.Put("a", []byte(`{`))))
+	assert.True(t, errors.IsInvalid(b.Write(map[string][]byte{"a": []byte(`1`), "b": []byte(`{`)}, nil)))
+
+	// nothing of a rejected write is kept
+	elements, err := b.List()
+	assert.NoError(t, err)
+	assert.Empty(t, elements)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
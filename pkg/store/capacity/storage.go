@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package capacitystorage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// NewStore generates a store object to save configured cell capacities
+func NewStore() Store {
+	return &store{}
+}
+
+// Store includes all functions for cell capacity storage
+type Store interface {
+	// SetCapacities replaces the configured cell capacities
+	SetCapacities(ctx context.Context, capacities Capacities) error
+
+	// GetCapacities gets the configured cell capacities
+	GetCapacities(ctx context.Context) (Capacities, error)
+
+	// Get gets the configured maximum number of UEs of the cell
+	Get(ctx context.Context, ids storage.IDs) (int, error)
+}
+
+type store struct {
+	capacities Capacities
+	mu         sync.RWMutex
+}
+
+func (s *store) SetCapacities(_ context.Context, capacities Capacities) error {
+	if err := capacities.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capacities = capacities
+	return nil
+}
+
+func (s *store) GetCapacities(_ context.Context) (Capacities, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.capacities, nil
+}
+
+func (s *store) Get(_ context.Context, ids storage.IDs) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, cell := range s.capacities.Cells {
+		if cell.PlmnID == ids.PlmnID && cell.CellID == ids.CellID {
+			return cell.MaxUEs, nil
+		}
+	}
+	return 0, errors.NewNotFound("capacity of cell %s:%s is not configured", ids.PlmnID, ids.CellID)
+}
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package capacitystorage
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CellCapacity is the capacity of a cell configured by the operator
+type CellCapacity struct {
+	PlmnID string `json:"plmn_id"`
+	CellID string `json:"cell_id"`
+	MaxUEs int    `json:"max_ues"`
+}
+
+// Capacities is the set of cell capacities configured by the operator
+type Capacities struct {
+	Cells []CellCapacity `json:"cells,omitempty"`
+}
+
+// Validate checks that each capacity is positive
+func (c Capacities) Validate() error {
+	for _, cell := range c.Cells {
+		if cell.MaxUEs <= 0 {
+			return errors.NewInvalid("max_ues of cell %s:%s should be positive", cell.PlmnID, cell.CellID)
+		}
+	}
+	return nil
+}
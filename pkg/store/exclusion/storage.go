@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exclusionstorage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// NewStore generates a store object to save allow and deny lists
+func NewStore() Store {
+	return &store{}
+}
+
+// Store includes all functions for exclusion list storage
+type Store interface {
+	// SetLists replaces the allow and deny lists
+	SetLists(ctx context.Context, lists Lists) error
+
+	// GetLists gets the allow and deny lists
+	GetLists(ctx context.Context) (Lists, error)
+
+	// IsCellExcluded returns true if the Ocns of the cell must not be changed
+	IsCellExcluded(ctx context.Context, ids storage.IDs) bool
+
+	// IsRelationExcluded returns true if the neighbor relation must not be used as an offload target
+	IsRelationExcluded(ctx context.Context, ids storage.IDs, nIDs storage.IDs) bool
+
+	// PutReport puts the report of the cells and the relations excluded in the last cycle
+	PutReport(ctx context.Context, report Report) error
+
+	// GetReport gets the report of the cells and the relations excluded in the last cycle
+	GetReport(ctx context.Context) (Report, error)
+}
+
+type store struct {
+	lists  Lists
+	report Report
+	mu     sync.RWMutex
+}
+
+func (s *store) SetLists(_ context.Context, lists Lists) error {
+	if err := lists.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists = lists
+	return nil
+}
+
+func (s *store) GetLists(_ context.Context) (Lists, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lists, nil
+}
+
+func (s *store) IsCellExcluded(_ context.Context, ids storage.IDs) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, r := range s.lists.DenyCells {
+		if r.Matches(ids) {
+			return true
+		}
+	}
+	if len(s.lists.AllowCells) == 0 {
+		return false
+	}
+	for _, r := range s.lists.AllowCells {
+		if r.Matches(ids) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *store) IsRelationExcluded(_ context.Context, ids storage.IDs, nIDs storage.IDs) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, r := range s.lists.DenyRelations {
+		if r.Matches(ids, nIDs) {
+			return true
+		}
+	}
+	if len(s.lists.AllowRelations) == 0 {
+		return false
+	}
+	for _, r := range s.lists.AllowRelations {
+		if r.Matches(ids, nIDs) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *store) PutReport(_ context.Context, report Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report = report
+	return nil
+}
+
+func (s *store) GetReport(_ context.Context) (Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.report, nil
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exclusionstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cellA = storage.IDs{NodeID: "e2:1", PlmnID: "138426", CellID: "a"}
+	cellB = storage.IDs{NodeID: "e2:1", PlmnID: "138426", CellID: "b"}
+	cellC = storage.IDs{NodeID: "e2:2", PlmnID: "138426", CellID: "c"}
+)
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		expected []storage.IDs
+	}{
+		{name: "cell ID", rule: Rule{CellID: "a"}, expected: []storage.IDs{cellA}},
+		{name: "E2 node ID", rule: Rule{NodeID: "e2:1"}, expected: []storage.IDs{cellA, cellB}},
+		{name: "all fields", rule: Rule{NodeID: "e2:1", PlmnID: "138426", CellID: "b"}, expected: []storage.IDs{cellB}},
+		{name: "fields of different cells", rule: Rule{NodeID: "e2:2", CellID: "a"}, expected: []storage.IDs{}},
+		{name: "empty rule", rule: Rule{}, expected: []storage.IDs{cellA, cellB, cellC}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matched := make([]storage.IDs, 0)
+			for _, ids := range []storage.IDs{cellA, cellB, cellC} {
+				if test.rule.Matches(ids) {
+					matched = append(matched, ids)
+				}
+			}
+			assert.Equal(t, test.expected, matched)
+		})
+	}
+}
+
+func TestIsCellExcluded(t *testing.T) {
+	tests := []struct {
+		name     string
+		lists    Lists
+		excluded []storage.IDs
+	}{
+		{name: "no lists", excluded: []storage.IDs{}},
+		{
+			name:     "deny",
+			lists:    Lists{DenyCells: []Rule{{CellID: "a"}}},
+			excluded: []storage.IDs{cellA},
+		},
+		{
+			name:     "allow excludes the others",
+			lists:    Lists{AllowCells: []Rule{{NodeID: "e2:1"}}},
+			excluded: []storage.IDs{cellC},
+		},
+		{
+			name:     "deny wins over allow",
+			lists:    Lists{AllowCells: []Rule{{NodeID: "e2:1"}}, DenyCells: []Rule{{CellID: "b"}}},
+			excluded: []storage.IDs{cellB, cellC},
+		},
+		{
+			name:     "relation rules do not exclude cells",
+			lists:    Lists{DenyRelations: []Relation{{Neighbor: Rule{CellID: "a"}}}},
+			excluded: []storage.IDs{},
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewStore()
+			assert.NoError(t, s.SetLists(ctx, test.lists))
+			excluded := make([]storage.IDs, 0)
+			for _, ids := range []storage.IDs{cellA, cellB, cellC} {
+				if s.IsCellExcluded(ctx, ids) {
+					excluded = append(excluded, ids)
+				}
+			}
+			assert.Equal(t, test.excluded, excluded)
+		})
+	}
+}
+
+func TestIsRelationExcluded(t *testing.T) {
+	type relation struct {
+		cell     storage.IDs
+		neighbor storage.IDs
+	}
+	all := []relation{{cellA, cellB}, {cellA, cellC}, {cellB, cellA}, {cellC, cellA}}
+	tests := []struct {
+		name     string
+		lists    Lists
+		excluded []relation
+	}{
+		{name: "no lists", excluded: []relation{}},
+		{
+			name:     "deny to the neighbor from any cell",
+			lists:    Lists{DenyRelations: []Relation{{Neighbor: Rule{CellID: "a"}}}},
+			excluded: []relation{{cellB, cellA}, {cellC, cellA}},
+		},
+		{
+			name:     "deny from the cell to the neighbor",
+			lists:    Lists{DenyRelations: []Relation{{Cell: Rule{CellID: "a"}, Neighbor: Rule{NodeID: "e2:2"}}}},
+			excluded: []relation{{cellA, cellC}},
+		},
+		{
+			name:     "allow excludes the others",
+			lists:    Lists{AllowRelations: []Relation{{Cell: Rule{CellID: "a"}, Neighbor: Rule{CellID: "b"}}}},
+			excluded: []relation{{cellA, cellC}, {cellB, cellA}, {cellC, cellA}},
+		},
+		{
+			name: "deny wins over allow",
+			lists: Lists{
+				AllowRelations: []Relation{{Neighbor: Rule{PlmnID: "138426"}}},
+				DenyRelations:  []Relation{{Cell: Rule{CellID: "b"}, Neighbor: Rule{CellID: "a"}}},
+			},
+			excluded: []relation{{cellB, cellA}},
+		},
+		{
+			name:     "cell rules do not exclude relations",
+			lists:    Lists{DenyCells: []Rule{{CellID: "a"}}},
+			excluded: []relation{},
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewStore()
+			assert.NoError(t, s.SetLists(ctx, test.lists))
+			excluded := make([]relation, 0)
+			for _, r := range all {
+				if s.IsRelationExcluded(ctx, r.cell, r.neighbor) {
+					excluded = append(excluded, r)
+				}
+			}
+			assert.Equal(t, test.excluded, excluded)
+		})
+	}
+}
+
+func TestSetListsInvalid(t *testing.T) {
+	ctx := context.Background()
+	for _, lists := range []Lists{
+		{AllowCells: []Rule{{}}},
+		{DenyCells: []Rule{{CellID: "a"}, {}}},
+		{AllowRelations: []Relation{{Cell: Rule{CellID: "a"}}}},
+		{DenyRelations: []Relation{{}}},
+	} {
+		s := NewStore()
+		assert.True(t, errors.IsInvalid(s.SetLists(ctx, lists)))
+		// the lists are not replaced with invalid ones
+		actual, err := s.GetLists(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, Lists{}, actual)
+	}
+}
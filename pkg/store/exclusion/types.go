@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exclusionstorage
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// Rule matches cells with E2 node ID, PLMN ID and cell ID; an empty field matches any value
+type Rule struct {
+	NodeID string `json:"node_id,omitempty"`
+	PlmnID string `json:"plmn_id,omitempty"`
+	CellID string `json:"cell_id,omitempty"`
+}
+
+// Matches returns true if the rule matches the cell
+func (r Rule) Matches(ids storage.IDs) bool {
+	return (r.NodeID == "" || r.NodeID == ids.NodeID) &&
+		(r.PlmnID == "" || r.PlmnID == ids.PlmnID) &&
+		(r.CellID == "" || r.CellID == ids.CellID)
+}
+
+// IsEmpty returns true if the rule matches any cell
+func (r Rule) IsEmpty() bool {
+	return r.NodeID == "" && r.PlmnID == "" && r.CellID == ""
+}
+
+// Relation matches neighbor relations from a serving cell to a neighbor cell;
+// an empty cell rule matches any serving cell
+type Relation struct {
+	Cell     Rule `json:"cell"`
+	Neighbor Rule `json:"neighbor"`
+}
+
+// Matches returns true if the relation matches the serving cell and the neighbor cell
+func (r Relation) Matches(ids storage.IDs, nIDs storage.IDs) bool {
+	return r.Cell.Matches(ids) && r.Neighbor.Matches(nIDs)
+}
+
+// Lists is the set of allow and deny lists.
+// A cell is excluded if it matches a deny rule or if there are allow rules and it matches none of them.
+// A neighbor relation is excluded in the same way with the relation rules.
+type Lists struct {
+	AllowCells     []Rule     `json:"allow_cells,omitempty"`
+	DenyCells      []Rule     `json:"deny_cells,omitempty"`
+	AllowRelations []Relation `json:"allow_relations,omitempty"`
+	DenyRelations  []Relation `json:"deny_relations,omitempty"`
+}
+
+// Validate checks that no rule matches every cell by mistake
+func (l Lists) Validate() error {
+	for _, rules := range [][]Rule{l.AllowCells, l.DenyCells} {
+		for _, r := range rules {
+			if r.IsEmpty() {
+				return errors.NewInvalid("cell rule should have at least one of node_id, plmn_id and cell_id")
+			}
+		}
+	}
+	for _, relations := range [][]Relation{l.AllowRelations, l.DenyRelations} {
+		for _, r := range relations {
+			if r.Neighbor.IsEmpty() {
+				return errors.NewInvalid("relation rule should have at least one of node_id, plmn_id and cell_id for neighbor")
+			}
+		}
+	}
+	return nil
+}
+
+// Report is the cells and the neighbor relations excluded in the last control cycle
+type Report struct {
+	Cells     []storage.IDs
+	Relations []RelationIDs
+}
+
+// RelationIDs is a neighbor relation from a serving cell to a neighbor cell
+type RelationIDs struct {
+	Cell     storage.IDs
+	Neighbor storage.IDs
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failurestorage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+const (
+	// maxBackoffFactor limits the backoff to quarantineBackoff * 2^maxBackoffFactor
+	maxBackoffFactor = 5
+)
+
+// NewStore generates a store object to save failure records of each cell
+func NewStore() Store {
+	return &store{
+		storage: make(map[storage.IDs]*Record),
+	}
+}
+
+// Store includes all functions for failure record storage
+type Store interface {
+	// RecordFailure records a failure of the cell; the cell is quarantined with exponential backoff
+	// when it fails threshold times in a row
+	RecordFailure(ctx context.Context, key storage.IDs, cause error, threshold int, backoff time.Duration) (*Record, error)
+
+	// RecordSuccess resets the consecutive failures of the cell
+	RecordSuccess(ctx context.Context, key storage.IDs) error
+
+	// Get gets the failure record of the cell
+	Get(ctx context.Context, key storage.IDs) (*Record, error)
+
+	// ListElements gets a copy of all failure records in this store
+	ListElements(ctx context.Context) ([]Record, error)
+
+	// Delete deletes the failure record of the cell
+	Delete(ctx context.Context, key storage.IDs) error
+}
+
+type store struct {
+	storage map[storage.IDs]*Record
+	mu      sync.RWMutex
+}
+
+func (s *store) RecordFailure(_ context.Context, key storage.IDs, cause error, threshold int, backoff time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.storage[key]
+	if !ok {
+		record = &Record{
+			Key: key,
+		}
+		s.storage[key] = record
+	}
+	now := time.Now()
+	record.ConsecutiveFailures++
+	record.TotalFailures++
+	record.LastError = cause.Error()
+	record.LastFailure = now
+	if threshold > 0 && record.ConsecutiveFailures >= threshold {
+		factor := record.ConsecutiveFailures - threshold
+		if factor > maxBackoffFactor {
+			factor = maxBackoffFactor
+		}
+		record.QuarantinedUntil = now.Add(backoff * time.Duration(1<<factor))
+	}
+	result := *record
+	return &result, nil
+}
+
+func (s *store) RecordSuccess(_ context.Context, key storage.IDs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.storage[key]; ok {
+		record.ConsecutiveFailures = 0
+		record.QuarantinedUntil = time.Time{}
+	}
+	return nil
+}
+
+func (s *store) Get(_ context.Context, key storage.IDs) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if record, ok := s.storage[key]; ok {
+		result := *record
+		return &result, nil
+	}
+	return nil, errors.NewNotFound("failure record not found")
+}
+
+func (s *store) ListElements(_ context.Context) ([]Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]Record, 0, len(s.storage))
+	for _, record := range s.storage {
+		result = append(result, *record)
+	}
+	return result, nil
+}
+
+func (s *store) Delete(_ context.Context, key storage.IDs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.storage, key)
+	return nil
+}
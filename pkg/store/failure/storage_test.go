@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failurestorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCell = storage.IDs{
+	NodeID: "e2:1",
+	PlmnID: "138426",
+	CellID: "c1",
+}
+
+func TestRecordFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		failures  int
+		// expected is the expected backoff in units of the base backoff after each failure; 0 is not quarantined
+		expected []int
+	}{
+		{
+			name:      "backoff doubles from the threshold",
+			threshold: 3,
+			failures:  6,
+			expected:  []int{0, 0, 1, 2, 4, 8},
+		},
+		{
+			name:      "backoff is capped",
+			threshold: 1,
+			failures:  8,
+			expected:  []int{1, 2, 4, 8, 16, 32, 32, 32},
+		},
+		{
+			name:      "quarantine is disabled",
+			threshold: 0,
+			failures:  4,
+			expected:  []int{0, 0, 0, 0},
+		},
+	}
+
+	ctx := context.Background()
+	backoff := time.Minute
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewStore()
+			for i := 0; i < test.failures; i++ {
+				record, err := s.RecordFailure(ctx, testCell, errors.NewInternal("failure %d", i), test.threshold, backoff)
+				assert.NoError(t, err)
+				assert.Equal(t, i+1, record.ConsecutiveFailures)
+				assert.Equal(t, i+1, record.TotalFailures)
+				assert.Equal(t, errors.NewInternal("failure %d", i).Error(), record.LastError)
+				if test.expected[i] == 0 {
+					assert.False(t, record.IsQuarantined(record.LastFailure))
+					continue
+				}
+				assert.Equal(t, backoff*time.Duration(test.expected[i]), record.QuarantinedUntil.Sub(record.LastFailure))
+			}
+		})
+	}
+}
+
+func TestRecordSuccess(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	for i := 0; i < 3; i++ {
+		_, err := s.RecordFailure(ctx, testCell, errors.NewInternal("failure"), 2, time.Minute)
+		assert.NoError(t, err)
+	}
+	record, err := s.Get(ctx, testCell)
+	assert.NoError(t, err)
+	assert.True(t, record.IsQuarantined(time.Now()))
+
+	// a success resets the consecutive failures and releases the cell but keeps the total
+	assert.NoError(t, s.RecordSuccess(ctx, testCell))
+	record, err = s.Get(ctx, testCell)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, record.ConsecutiveFailures)
+	assert.Equal(t, 3, record.TotalFailures)
+	assert.False(t, record.IsQuarantined(time.Now()))
+
+	// the backoff starts over after the reset
+	record, err = s.RecordFailure(ctx, testCell, errors.NewInternal("failure"), 2, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, record.IsQuarantined(time.Now()))
+	record, err = s.RecordFailure(ctx, testCell, errors.NewInternal("failure"), 2, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, record.QuarantinedUntil.Sub(record.LastFailure))
+
+	assert.NoError(t, s.Delete(ctx, testCell))
+	_, err = s.Get(ctx, testCell)
+	assert.True(t, errors.IsNotFound(err))
+	records, err := s.ListElements(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestIsQuarantined(t *testing.T) {
+	now := time.Now()
+	record := Record{
+		QuarantinedUntil: now.Add(time.Minute),
+	}
+	assert.True(t, record.IsQuarantined(now))
+	// the cell is released when the backoff expires
+	assert.False(t, record.IsQuarantined(now.Add(time.Minute)))
+	assert.False(t, (&Record{}).IsQuarantined(now))
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failurestorage
+
+import (
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// Record is the failure record of a cell
+type Record struct {
+	Key                 storage.IDs
+	ConsecutiveFailures int
+	TotalFailures       int
+	LastError           string
+	LastFailure         time.Time
+	QuarantinedUntil    time.Time
+}
+
+// IsQuarantined returns true if the cell is quarantined at the given time
+func (r *Record) IsQuarantined(now time.Time) bool {
+	return now.Before(r.QuarantinedUntil)
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package historystorage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// NewStore generates a store object to save time series of at most maxPoints points per key
+// and decision records of at most maxPoints cycles, each kept for the retention
+func NewStore(retention time.Duration, maxPoints int) Store {
+	return &store{
+		series:    make(map[Key]*ring),
+		decisions: make([]DecisionRecord, 0),
+		retention: retention,
+		maxPoints: maxPoints,
+	}
+}
+
+// Store includes all functions for time series storage
+type Store interface {
+	// Append appends a point to the time series of the key
+	Append(ctx context.Context, key Key, point Point) error
+
+	// Query gets the points of the time series of the key from the time to the time;
+	// if step is positive, the points are averaged in each step
+	Query(ctx context.Context, key Key, from time.Time, to time.Time, step time.Duration) ([]Point, error)
+
+	// ListKeys gets the keys of all time series in this store
+	ListKeys(ctx context.Context) ([]Key, error)
+
+	// AppendDecision appends the decision of a control cycle;
+	// it also evicts the time series without any point in the retention
+	AppendDecision(ctx context.Context, record DecisionRecord) error
+
+	// DeleteCell deletes the time series of the cell and those of the relations from or to the cell;
+	// neighbor IDs do not have E2 node ID, so the cell is matched with PLMN ID and cell ID
+	DeleteCell(ctx context.Context, ids storage.IDs) error
+
+	// QueryDecisions gets the decisions made from the time to the time
+	QueryDecisions(ctx context.Context, from time.Time, to time.Time) ([]DecisionRecord, error)
+}
+
+type store struct {
+	series    map[Key]*ring
+	decisions []DecisionRecord
+	retention time.Duration
+	maxPoints int
+	mu        sync.RWMutex
+}
+
+func (s *store) Append(_ context.Context, key Key, point Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.series[key]
+	if !ok {
+		r = newRing(s.maxPoints)
+		s.series[key] = r
+	}
+	r.push(point)
+	r.dropBefore(point.Time.Add(-s.retention))
+	return nil
+}
+
+func (s *store) Query(_ context.Context, key Key, from time.Time, to time.Time, step time.Duration) ([]Point, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.series[key]
+	if !ok {
+		return nil, errors.NewNotFound("time series %v does not exist", key)
+	}
+	if limit := time.Now().Add(-s.retention); from.Before(limit) {
+		from = limit
+	}
+	return downsample(r.between(from, to), from, step), nil
+}
+
+func (s *store) ListKeys(_ context.Context) ([]Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]Key, 0, len(s.series))
+	for key := range s.series {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func (s *store) AppendDecision(_ context.Context, record DecisionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decisions = append(s.decisions, record)
+	limit := record.Time.Add(-s.retention)
+	i := 0
+	for i < len(s.decisions) && (len(s.decisions)-i > s.maxPoints || s.decisions[i].Time.Before(limit)) {
+		i++
+	}
+	s.decisions = s.decisions[i:]
+
+	// the series of the cells and relations which are gone are not appended any longer
+	for key, r := range s.series {
+		r.dropBefore(limit)
+		if r.size == 0 {
+			delete(s.series, key)
+		}
+	}
+	return nil
+}
+
+func (s *store) DeleteCell(_ context.Context, ids storage.IDs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.series {
+		if key.Cell == ids || (key.Neighbor.PlmnID == ids.PlmnID && key.Neighbor.CellID == ids.CellID) {
+			delete(s.series, key)
+		}
+	}
+	return nil
+}
+
+func (s *store) QueryDecisions(_ context.Context, from time.Time, to time.Time) ([]DecisionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]DecisionRecord, 0)
+	for _, d := range s.decisions {
+		if d.Time.Before(from) || d.Time.After(to) {
+			continue
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package historystorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cellA     = storage.IDs{NodeID: "e2:1", PlmnID: "138426", CellID: "a"}
+	cellB     = storage.IDs{NodeID: "e2:2", PlmnID: "138426", CellID: "b"}
+	neighborA = storage.IDs{PlmnID: "138426", CellID: "a"}
+	neighborB = storage.IDs{PlmnID: "138426", CellID: "b"}
+)
+
+func TestStoreQuery(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(time.Hour, 3)
+	key := Key{Metric: "numUEs", Cell: cellA}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, s.Append(ctx, key, Point{Time: now.Add(time.Duration(i) * time.Second), Value: float64(i)}))
+	}
+
+	// the series keeps the latest points only
+	points, err := s.Query(ctx, key, now, now.Add(time.Minute), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 3, 4}, values(points))
+
+	points, err = s.Query(ctx, key, now, now.Add(time.Minute), time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3}, values(points))
+
+	_, err = s.Query(ctx, Key{Metric: "numUEs", Cell: cellB}, now, now.Add(time.Minute), 0)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestStoreRetention(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(time.Minute, 10)
+	key := Key{Metric: "numUEs", Cell: cellA}
+	now := time.Now()
+
+	// a point appended drops the points older than the retention before it
+	assert.NoError(t, s.Append(ctx, key, Point{Time: now.Add(-3 * time.Minute), Value: 1}))
+	assert.NoError(t, s.Append(ctx, key, Point{Time: now.Add(-30 * time.Second), Value: 2}))
+	assert.NoError(t, s.Append(ctx, key, Point{Time: now, Value: 3}))
+	points, err := s.Query(ctx, key, now.Add(-time.Hour), now, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 3}, values(points))
+}
+
+func TestStoreEviction(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	keys := []Key{
+		{Metric: "numUEs", Cell: cellA},
+		{Metric: "numUEs", Cell: cellB},
+		{Metric: "ocn", Cell: cellA, Neighbor: neighborB},
+		{Metric: "ocn", Cell: cellB, Neighbor: neighborA},
+	}
+	tests := []struct {
+		name     string
+		apply    func(s Store) error
+		expected []Key
+	}{
+		{
+			name: "removed cell and relations from and to it",
+			apply: func(s Store) error {
+				return s.DeleteCell(ctx, cellB)
+			},
+			expected: []Key{keys[0]},
+		},
+		{
+			name: "series without points in the retention",
+			apply: func(s Store) error {
+				// cell b is not appended any longer after it is gone
+				if err := s.Append(ctx, keys[0], Point{Time: now.Add(2 * time.Minute)}); err != nil {
+					return err
+				}
+				if err := s.Append(ctx, keys[2], Point{Time: now.Add(2 * time.Minute)}); err != nil {
+					return err
+				}
+				return s.AppendDecision(ctx, DecisionRecord{Time: now.Add(2 * time.Minute)})
+			},
+			expected: []Key{keys[0], keys[2]},
+		},
+		{
+			name: "series with points in the retention",
+			apply: func(s Store) error {
+				return s.AppendDecision(ctx, DecisionRecord{Time: now.Add(30 * time.Second)})
+			},
+			expected: keys,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewStore(time.Minute, 10)
+			for _, key := range keys {
+				assert.NoError(t, s.Append(ctx, key, Point{Time: now, Value: 1}))
+			}
+			assert.NoError(t, test.apply(s))
+			actual, err := s.ListKeys(ctx)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.expected, actual)
+		})
+	}
+}
+
+func TestStoreDecisions(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(time.Minute, 3)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, s.AppendDecision(ctx, DecisionRecord{Time: now.Add(time.Duration(i) * time.Second), Algorithm: "pid"}))
+	}
+
+	// at most maxPoints decisions are kept
+	decisions, err := s.QueryDecisions(ctx, now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, decisions, 3)
+	assert.Equal(t, now.Add(2*time.Second), decisions[0].Time)
+
+	// and none older than the retention
+	assert.NoError(t, s.AppendDecision(ctx, DecisionRecord{Time: now.Add(2 * time.Minute)}))
+	decisions, err = s.QueryDecisions(ctx, now, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, decisions, 1)
+}
+
+func values(points []Point) []float64 {
+	result := make([]float64, 0, len(points))
+	for _, p := range points {
+		result = append(result, p.Value)
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package historystorage
+
+import (
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+const (
+	// MetricLoad is the metric of the load of a cell in percent
+	MetricLoad = "load"
+
+	// MetricOcn is the metric of the Ocn from a serving cell to a neighbor cell
+	MetricOcn = "ocn"
+)
+
+// Key is the key of a time series
+type Key struct {
+	Metric string
+	Cell   storage.IDs
+	// Neighbor is empty for the metrics of a cell
+	Neighbor storage.IDs
+}
+
+// Point is a value of a time series at a time
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// DecisionRecord is the decision made in a control cycle
+type DecisionRecord struct {
+	Time      time.Time
+	Algorithm string
+	Shadow    bool
+	Ocns      map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+	Errors    map[storage.IDs]string
+}
+
+// ring is a ring buffer of points ordered by time
+type ring struct {
+	points []Point
+	start  int
+	size   int
+}
+
+func newRing(capacity int) *ring {
+	return &ring{
+		points: make([]Point, capacity),
+	}
+}
+
+// push appends a point and overwrites the oldest point if the ring is full
+func (r *ring) push(p Point) {
+	if len(r.points) == 0 {
+		return
+	}
+	if r.size < len(r.points) {
+		r.points[(r.start+r.size)%len(r.points)] = p
+		r.size++
+		return
+	}
+	r.points[r.start] = p
+	r.start = (r.start + 1) % len(r.points)
+}
+
+// dropBefore drops the points older than the time
+func (r *ring) dropBefore(t time.Time) {
+	for r.size > 0 && r.points[r.start].Time.Before(t) {
+		r.start = (r.start + 1) % len(r.points)
+		r.size--
+	}
+}
+
+// between returns the points from the time to the time inclusive
+func (r *ring) between(from time.Time, to time.Time) []Point {
+	result := make([]Point, 0)
+	for i := 0; i < r.size; i++ {
+		p := r.points[(r.start+i)%len(r.points)]
+		if p.Time.Before(from) || p.Time.After(to) {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// downsample averages the points in each step from the start time;
+// each averaged point has the start time of its step
+func downsample(points []Point, from time.Time, step time.Duration) []Point {
+	if step <= 0 || len(points) == 0 {
+		return points
+	}
+	result := make([]Point, 0)
+	bucket := int64(-1)
+	sum := 0.0
+	count := 0
+	flush := func() {
+		if count > 0 {
+			result = append(result, Point{
+				Time:  from.Add(time.Duration(bucket) * step),
+				Value: sum / float64(count),
+			})
+		}
+	}
+	for _, p := range points {
+		b := int64(p.Time.Sub(from) / step)
+		if b != bucket {
+			flush()
+			bucket = b
+			sum = 0
+			count = 0
+		}
+		sum += p.Value
+		count++
+	}
+	flush()
+	return result
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package historystorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Unix(1000, 0)
+
+// testPoints generates the points whose values are the seconds after the start
+func testPoints(seconds ...int) []Point {
+	points := make([]Point, 0, len(seconds))
+	for _, s := range seconds {
+		points = append(points, Point{
+			Time:  start.Add(time.Duration(s) * time.Second),
+			Value: float64(s),
+		})
+	}
+	return points
+}
+
+func TestRing(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		pushed   []int
+		before   int
+		expected []int
+	}{
+		{name: "within the capacity", capacity: 3, pushed: []int{1, 2}, expected: []int{1, 2}},
+		{name: "full", capacity: 3, pushed: []int{1, 2, 3}, expected: []int{1, 2, 3}},
+		{name: "wraparound overwrites the oldest", capacity: 3, pushed: []int{1, 2, 3, 4, 5}, expected: []int{3, 4, 5}},
+		{name: "wraparound twice", capacity: 2, pushed: []int{1, 2, 3, 4, 5}, expected: []int{4, 5}},
+		{name: "drop before", capacity: 3, pushed: []int{1, 2, 3}, before: 2, expected: []int{2, 3}},
+		{name: "drop before after wraparound", capacity: 3, pushed: []int{1, 2, 3, 4, 5}, before: 5, expected: []int{5}},
+		{name: "drop all", capacity: 3, pushed: []int{1, 2}, before: 3, expected: []int{}},
+		{name: "no capacity", capacity: 0, pushed: []int{1, 2}, expected: []int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRing(test.capacity)
+			for _, p := range testPoints(test.pushed...) {
+				r.push(p)
+			}
+			if test.before > 0 {
+				r.dropBefore(start.Add(time.Duration(test.before) * time.Second))
+			}
+			assert.Equal(t, len(test.expected), r.size)
+			assert.Equal(t, testPoints(test.expected...), r.between(start, start.Add(time.Hour)))
+		})
+	}
+
+	// between is inclusive on both ends
+	r := newRing(5)
+	for _, p := range testPoints(1, 2, 3, 4, 5) {
+		r.push(p)
+	}
+	assert.Equal(t, testPoints(2, 3, 4), r.between(start.Add(2*time.Second), start.Add(4*time.Second)))
+}
+
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []Point
+		step     time.Duration
+		expected []Point
+	}{
+		{
+			name:     "no step",
+			points:   testPoints(1, 2, 3),
+			expected: testPoints(1, 2, 3),
+		},
+		{
+			name:     "no points",
+			points:   testPoints(),
+			step:     time.Minute,
+			expected: testPoints(),
+		},
+		{
+			name:   "average in each step from the start",
+			points: testPoints(0, 2, 4, 10, 11),
+			step:   10 * time.Second,
+			expected: []Point{
+				{Time: start, Value: 2},
+				{Time: start.Add(10 * time.Second), Value: 10.5},
+			},
+		},
+		{
+			name:   "empty steps are skipped",
+			points: testPoints(1, 25),
+			step:   10 * time.Second,
+			expected: []Point{
+				{Time: start, Value: 1},
+				{Time: start.Add(20 * time.Second), Value: 25},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, downsample(test.points, start, test.step))
+		})
+	}
+}
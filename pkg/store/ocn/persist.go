@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocnstorage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/backend"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/onosproject/onos-mlb/pkg/store/watcher"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+// NewStoreWithBackend generates a store object which reloads the Ocns persisted in the backend
+// and persists the changes of them on every flush; frozen Ocns are not persisted
+func NewStoreWithBackend(b backend.Backend) (Store, error) {
+	s := &store{
+		storage:  make(map[storage.IDs]*OcnMap),
+		frozen:   make(map[storage.IDs]map[storage.IDs]time.Time),
+		watchers: watcher.NewWatchers(),
+		backend:  b,
+		dirty:    make(map[storage.IDs]bool),
+	}
+	elements, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range elements {
+		var p persistedOcnMap
+		if err := json.Unmarshal(value, &p); err != nil {
+			return nil, err
+		}
+		ocnMap := &OcnMap{
+			Value: make(map[storage.IDs]meastype.QOffsetRange),
+		}
+		for _, ocn := range p.Ocns {
+			ocnMap.Value[ocn.Key] = meastype.QOffsetRange(ocn.Value)
+		}
+		s.storage[p.Key] = ocnMap
+	}
+	log.Infof("Reloaded Ocns of %v cells", len(s.storage))
+	return s, nil
+}
+
+// persistedOcnMap is the Ocn map of a cell in the backend; a JSON object cannot have struct keys
+type persistedOcnMap struct {
+	Key  storage.IDs    `json:"key"`
+	Ocns []persistedOcn `json:"ocns"`
+}
+
+type persistedOcn struct {
+	Key   storage.IDs `json:"key"`
+	Value int         `json:"value"`
+}
+
+func backendKey(key storage.IDs) string {
+	bytes, _ := json.Marshal(key)
+	return string(bytes)
+}
+
+// persist marks the Ocn map of the key to be written into the backend on the next flush;
+// it has to be called with the lock held
+func (s *store) persist(key storage.IDs) {
+	if s.backend == nil {
+		return
+	}
+	s.dirty[key] = true
+}
+
+func (s *store) Flush(_ context.Context) error {
+	if s.backend == nil {
+		return nil
+	}
+	// flushes are serialized so that an older flush does not overwrite a newer one
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	puts := make(map[string][]byte)
+	deletes := make([]string, 0)
+	keys := make([]storage.IDs, 0, len(s.dirty))
+	for key := range s.dirty {
+		keys = append(keys, key)
+		ocnMap, ok := s.storage[key]
+		if !ok {
+			deletes = append(deletes, backendKey(key))
+			continue
+		}
+		bytes, err := encodeOcnMap(key, ocnMap)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		puts[backendKey(key)] = bytes
+	}
+	s.dirty = make(map[storage.IDs]bool)
+	s.mu.Unlock()
+
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := s.backend.Write(puts, deletes); err != nil {
+		// retry with the latest Ocn maps on the next flush
+		s.mu.Lock()
+		for _, key := range keys {
+			s.dirty[key] = true
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func encodeOcnMap(key storage.IDs, ocnMap *OcnMap) ([]byte, error) {
+	p := persistedOcnMap{
+		Key:  key,
+		Ocns: make([]persistedOcn, 0, len(ocnMap.Value)),
+	}
+	for k, v := range ocnMap.Value {
+		p.Ocns = append(p.Ocns, persistedOcn{
+			Key:   k,
+			Value: int(v),
+		})
+	}
+	return json.Marshal(p)
+}
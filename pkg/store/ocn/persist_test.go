@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocnstorage
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/backend"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBackend records the writes into the file backend and fails them while fail is set
+type testBackend struct {
+	backend.Backend
+	writes [][]string
+	fail   bool
+}
+
+func (b *testBackend) Write(puts map[string][]byte, deletes []string) error {
+	if b.fail {
+		return errors.NewInternal("backend is down")
+	}
+	keys := make([]string, 0, len(puts)+len(deletes))
+	for key := range puts {
+		keys = append(keys, key)
+	}
+	keys = append(keys, deletes...)
+	sort.Strings(keys)
+	b.writes = append(b.writes, keys)
+	return b.Backend.Write(puts, deletes)
+}
+
+func newTestBackend(t *testing.T, path string) *testBackend {
+	b, err := backend.NewFileBackend(path)
+	assert.NoError(t, err)
+	return &testBackend{
+		Backend: b,
+	}
+}
+
+func TestFlush(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		apply    func(s Store)
+		writes   [][]string
+		expected map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+	}{
+		{
+			name: "changes of several keys are written at once",
+			apply: func(s Store) {
+				_ = s.PutInnerMapElem(ctx, cellA, neighborB, meastype.QOffset1dB)
+				_ = s.PutInnerMapElem(ctx, cellA, neighborB, meastype.QOffset2dB)
+				_ = s.PutInnerMapElems(ctx, cellB, map[storage.IDs]meastype.QOffsetRange{neighborA: meastype.QOffset3dB})
+			},
+			writes: [][]string{{backendKey(cellA), backendKey(cellB)}},
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset2dB},
+				cellB: {neighborA: meastype.QOffset3dB},
+			},
+		},
+		{
+			name:   "nothing is written without changes",
+			apply:  func(s Store) {},
+			writes: nil,
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset0dB},
+				cellB: {neighborA: meastype.QOffset0dB},
+			},
+		},
+		{
+			name: "deleted key is deleted from the backend",
+			apply: func(s Store) {
+				_ = s.Delete(ctx, cellB)
+			},
+			writes: [][]string{{backendKey(cellB)}},
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset0dB},
+			},
+		},
+		{
+			name: "frozen Ocns are not persisted",
+			apply: func(s Store) {
+				_ = s.FreezeInnerElement(ctx, cellA, neighborB, time.Now().Add(time.Hour))
+			},
+			writes: nil,
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset0dB},
+				cellB: {neighborA: meastype.QOffset0dB},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ocn.json")
+			b := newTestBackend(t, path)
+			s, err := NewStoreWithBackend(b)
+			assert.NoError(t, err)
+			newTestStore(t, s)
+			assert.NoError(t, s.Flush(ctx))
+			b.writes = nil
+
+			test.apply(s)
+			assert.NoError(t, s.Flush(ctx))
+			assert.Equal(t, test.writes, b.writes)
+
+			// a new store of the same file reloads the Ocns after a restart
+			reloaded, err := NewStoreWithBackend(newTestBackend(t, path))
+			assert.NoError(t, err)
+			keys, err := reloaded.ListKeys(ctx)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, keysOf(test.expected), keys)
+			assertOcns(t, reloaded, test.expected)
+			assert.False(t, reloaded.IsInnerElementFrozen(ctx, cellA, neighborB))
+		})
+	}
+}
+
+func TestFlushRetry(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ocn.json")
+	b := newTestBackend(t, path)
+	s, err := NewStoreWithBackend(b)
+	assert.NoError(t, err)
+	newTestStore(t, s)
+
+	// a failed flush keeps the keys dirty so that the next flush writes them
+	b.fail = true
+	assert.Error(t, s.Flush(ctx))
+	b.fail = false
+	assert.NoError(t, s.PutInnerMapElem(ctx, cellA, neighborB, meastype.QOffset1dB))
+	assert.NoError(t, s.Flush(ctx))
+	assert.Equal(t, [][]string{{backendKey(cellA), backendKey(cellB)}}, b.writes)
+
+	reloaded, err := NewStoreWithBackend(newTestBackend(t, path))
+	assert.NoError(t, err)
+	assertOcns(t, reloaded, map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+		cellA: {neighborB: meastype.QOffset1dB},
+		cellB: {neighborA: meastype.QOffset0dB},
+	})
+}
+
+func TestNewStoreWithBackendCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ocn.json")
+	b := newTestBackend(t, path)
+	assert.NoError(t, b.Put("key", []byte(`{"key": 1}`)))
+	_, err := NewStoreWithBackend(b)
+	assert.Error(t, err)
+}
@@ -8,10 +8,11 @@ package ocnstorage
 import (
 	"context"
 	"sync"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-mlb/pkg/store/backend"
 	"github.com/onosproject/onos-mlb/pkg/store/event"
 	"github.com/onosproject/onos-mlb/pkg/store/storage"
 	"github.com/onosproject/onos-mlb/pkg/store/watcher"
@@ -20,40 +21,22 @@ import (
 
 var log = logging.GetLogger()
 
-// NewStore generates a store object to save Ocn into a map
+var _ Store = &store{} // to check interface and struct in compile time (static check)
+
+// NewStore generates a store object to save Ocn into a map in memory
 func NewStore() Store {
 	watchers := watcher.NewWatchers()
 	return &store{
 		storage:  make(map[storage.IDs]*OcnMap),
+		frozen:   make(map[storage.IDs]map[storage.IDs]time.Time),
 		watchers: watchers,
 	}
 }
 
-// Store has all functions in this store
+// Store has all functions in this store; it is the store of the Ocn map of each cell
+// with the functions for the Ocns in the maps
 type Store interface {
-	// Put puts key and its value
-	Put(ctx context.Context, key storage.IDs, value *OcnMap) (*OcnMap, error)
-
-	// Get gets the element with key
-	Get(ctx context.Context, key storage.IDs) (*OcnMap, error)
-
-	// ListElements gets all elements in this store
-	ListElements(ctx context.Context, ch chan<- *OcnMap) error
-
-	// ListKeys gets all keys in this store
-	ListKeys(ctx context.Context, ch chan<- storage.IDs) error
-
-	// Update updates an element
-	Update(ctx context.Context, entry *OcnMap, key storage.IDs) error
-
-	// Delete deletes an element
-	Delete(ctx context.Context, key storage.IDs) error
-
-	// Watch watches the event of this store
-	Watch(ctx context.Context, ch chan<- event.Event) error
-
-	// Print prints the map in this store for debugging
-	Print()
+	storage.Store[storage.IDs, *OcnMap]
 
 	// PutInnerMapElem puts inner key and its value into the inner map
 	PutInnerMapElem(ctx context.Context, key storage.IDs, innerKey storage.IDs, value meastype.QOffsetRange) error
@@ -61,116 +44,175 @@ type Store interface {
 	// PutInnerMapElems puts multiple OCNs into the inner map
 	PutInnerMapElems(ctx context.Context, key storage.IDs, ocns map[storage.IDs]meastype.QOffsetRange) error
 
+	// Begin begins a transaction to put the Ocns of several cells all at once
+	Begin(ctx context.Context) Txn
+
+	// Flush writes the Ocn maps changed since the last flush into the backend at once, if the store has a backend;
+	// the store is not locked while they are written
+	Flush(ctx context.Context) error
+
 	// GetInnerMapElem gets inner element with inner key
 	GetInnerMapElem(ctx context.Context, key storage.IDs, innerKey storage.IDs) (meastype.QOffsetRange, error)
 
 	// UpdateInnerMapElem gets inner element with inner key
 	UpdateInnerMapElem(ctx context.Context, key storage.IDs, innerKey storage.IDs, value meastype.QOffsetRange) error
 
-	// ListAllInnerElement gets all inner element in this store
-	ListAllInnerElement(ctx context.Context, ch chan<- Entry) error
+	// ListAllInnerElement gets a copy of all inner elements in this store
+	ListAllInnerElement(ctx context.Context) ([]Entry, error)
 
-	// ListInnerElement gets all inner element in this store
-	ListInnerElement(ctx context.Context, key storage.IDs, ch chan<- InnerEntry) error
+	// ListInnerElement gets a copy of the inner elements of the key
+	ListInnerElement(ctx context.Context, key storage.IDs) ([]InnerEntry, error)
 
 	// DeleteInnerElement deletes an inner element
 	DeleteInnerElement(ctx context.Context, key storage.IDs, innerKey storage.IDs) error
+
+	// FreezeInnerElement freezes the Ocn of an inner element until the given time
+	FreezeInnerElement(ctx context.Context, key storage.IDs, innerKey storage.IDs, until time.Time) error
+
+	// IsInnerElementFrozen returns true if the Ocn of an inner element is frozen
+	IsInnerElementFrozen(ctx context.Context, key storage.IDs, innerKey storage.IDs) bool
+
+	// ListFrozenElements gets all frozen inner elements in this store
+	ListFrozenElements(ctx context.Context) ([]FrozenEntry, error)
+
+	// ListViolations gets all neighbor pairs whose |Ocn(A->B) + Ocn(B->A)| is larger than maxSum;
+	// Ocns are counted in steps of the Ocn range from QOffset0dB and each pair is reported once
+	ListViolations(ctx context.Context, maxSum int) ([]Violation, error)
 }
 
 type store struct {
 	storage  map[storage.IDs]*OcnMap
+	frozen   map[storage.IDs]map[storage.IDs]time.Time
 	mu       sync.RWMutex
 	watchers *watcher.Watchers
+	backend  backend.Backend
+	dirty    map[storage.IDs]bool
+	flushMu  sync.Mutex
 }
 
-func (s *store) Put(_ context.Context, key storage.IDs, value *OcnMap) (*OcnMap, error) {
+func (s *store) Put(_ context.Context, key storage.IDs, value *OcnMap) (*storage.Entry[storage.IDs, *OcnMap], error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.storage[key] = value
+	// the store keeps its own copy so that the caller can change the value afterwards
+	s.storage[key] = copyOcnMap(value)
+	s.persist(key)
 	s.watchers.Send(event.Event{
 		Key:   key,
-		Value: value,
+		Value: copyOcnMap(value),
 		Type:  storage.Created,
 	})
-	return value, nil
+	return &storage.Entry[storage.IDs, *OcnMap]{
+		Key:   key,
+		Value: value,
+	}, nil
 }
 
-func (s *store) Get(_ context.Context, key storage.IDs) (*OcnMap, error) {
+func (s *store) Get(_ context.Context, key storage.IDs) (*storage.Entry[storage.IDs, *OcnMap], error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	if v, ok := s.storage[key]; ok {
-		return v, nil
+		return &storage.Entry[storage.IDs, *OcnMap]{
+			Key:   key,
+			Value: copyOcnMap(v),
+		}, nil
 	}
 	return nil, errors.New(errors.NotFound, "the storage entry does not exist")
 }
 
-func (s *store) ListElements(_ context.Context, ch chan<- *OcnMap) error {
+func (s *store) ListElements(_ context.Context, opts ...storage.ListOption[storage.IDs, *OcnMap]) ([]*storage.Entry[storage.IDs, *OcnMap], error) {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if len(s.storage) == 0 {
-		return errors.New(errors.NotFound, "no storage entries stored")
+	entries := make([]*storage.Entry[storage.IDs, *OcnMap], 0, len(s.storage))
+	for key, value := range s.storage {
+		entries = append(entries, &storage.Entry[storage.IDs, *OcnMap]{
+			Key:   key,
+			Value: copyOcnMap(value),
+		})
 	}
+	s.mu.RUnlock()
+	return storage.Select(entries, opts...), nil
+}
 
-	for _, entry := range s.storage {
-		ch <- entry
+// copyOcnMap copies the Ocn map so that the caller does not race with the changes of the store;
+// a nil map is copied as an empty one
+func copyOcnMap(value *OcnMap) *OcnMap {
+	if value == nil {
+		return &OcnMap{
+			Value: make(map[storage.IDs]meastype.QOffsetRange),
+		}
+	}
+	ocns := make(map[storage.IDs]meastype.QOffsetRange, len(value.Value))
+	for k, v := range value.Value {
+		ocns[k] = v
+	}
+	return &OcnMap{
+		Value: ocns,
 	}
-	close(ch)
-	return nil
 }
 
-func (s *store) ListKeys(_ context.Context, ch chan<- storage.IDs) error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if len(s.storage) == 0 {
-		return errors.New(errors.NotFound, "no storage entries stored")
+func (s *store) ListKeys(ctx context.Context, opts ...storage.ListOption[storage.IDs, *OcnMap]) ([]storage.IDs, error) {
+	entries, err := s.ListElements(ctx, opts...)
+	if err != nil {
+		return nil, err
 	}
-
-	for key := range s.storage {
-		ch <- key
+	keys := make([]storage.IDs, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
 	}
-	close(ch)
-	return nil
+	return keys, nil
 }
 
-func (s *store) Update(_ context.Context, entry *OcnMap, key storage.IDs) error {
+func (s *store) Update(_ context.Context, entry *storage.Entry[storage.IDs, *OcnMap]) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if _, ok := s.storage[key]; ok {
-		s.storage[key] = entry
+	if _, ok := s.storage[entry.Key]; ok {
+		s.storage[entry.Key] = copyOcnMap(entry.Value)
+		s.persist(entry.Key)
 		s.watchers.Send(event.Event{
-			Key:   key,
-			Value: entry,
+			Key:   entry.Key,
+			Value: copyOcnMap(entry.Value),
 			Type:  storage.Updated,
 		})
+		return nil
 	}
 
-	return errors.New(errors.NotFound, "no storage entry does not exist; put the entry first")
+	return errors.New(errors.NotFound, "the storage entry does not exist; put the entry first")
 }
 
 func (s *store) Delete(_ context.Context, key storage.IDs) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	value, ok := s.storage[key]
+	if !ok {
+		return nil
+	}
 	delete(s.storage, key)
+	delete(s.frozen, key)
+	s.persist(key)
+	s.watchers.Send(event.Event{
+		Key:   key,
+		Value: value,
+		Type:  storage.Deleted,
+	})
 	return nil
 }
 
-func (s *store) Watch(ctx context.Context, ch chan<- event.Event) error {
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
-		log.Error(err)
-		close(ch)
-		return err
+func (s *store) Watch(ctx context.Context, ch chan<- event.Event, opts ...watcher.Option) error {
+	// the read lock keeps the events from being sent between the replay and the registration
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watchers.Watch(ctx, ch, s.replay, opts...)
+}
+
+func (s *store) replay() []event.Event {
+	events := make([]event.Event, 0, len(s.storage))
+	for key, value := range s.storage {
+		events = append(events, event.Event{
+			Key:   key,
+			Value: copyOcnMap(value),
+			Type:  storage.Created,
+		})
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-	}()
-	return nil
+	return events
 }
 
 func (s *store) Print() {
@@ -188,6 +230,7 @@ func (s *store) PutInnerMapElem(_ context.Context, key storage.IDs, innerKey sto
 		return errors.NewNotFound("inner map does not exist")
 	}
 	s.storage[key].Value[innerKey] = value
+	s.persist(key)
 	return nil
 }
 
@@ -201,16 +244,22 @@ func (s *store) PutInnerMapElems(_ context.Context, key storage.IDs, ocns map[st
 	for k, v := range ocns {
 		s.storage[key].Value[k] = v
 	}
+	s.persist(key)
 	return nil
 }
 
 func (s *store) GetInnerMapElem(_ context.Context, key storage.IDs, innerKey storage.IDs) (meastype.QOffsetRange, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	if _, ok := s.storage[key].Value[innerKey]; !ok {
+	ocnMap, ok := s.storage[key]
+	if !ok {
+		return 0, errors.NewNotFound("inner map does not exist")
+	}
+	ocn, ok := ocnMap.Value[innerKey]
+	if !ok {
 		return 0, errors.NewNotFound("element does not exist")
 	}
-	return s.storage[key].Value[innerKey], nil
+	return ocn, nil
 }
 
 func (s *store) UpdateInnerMapElem(_ context.Context, key storage.IDs, innerKey storage.IDs, value meastype.QOffsetRange) error {
@@ -220,53 +269,144 @@ func (s *store) UpdateInnerMapElem(_ context.Context, key storage.IDs, innerKey
 		return errors.NewNotFound("inner map does not exist")
 	}
 	s.storage[key].Value[innerKey] = value
+	s.persist(key)
 	return nil
 }
 
-func (s *store) ListAllInnerElement(_ context.Context, ch chan<- Entry) error {
+func (s *store) ListAllInnerElement(_ context.Context) ([]Entry, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	if len(s.storage) == 0 {
-		return errors.New(errors.NotFound, "no storage entries stored")
-	}
-
+	result := make([]Entry, 0)
 	for k, v := range s.storage {
-		if len(v.Value) == 0 {
-			return errors.New(errors.NotFound, "no inner map in storage stored")
-		}
 		for ik, iv := range v.Value {
-			ch <- Entry{
+			result = append(result, Entry{
 				Key: k,
 				Value: InnerEntry{
 					Key:   ik,
 					Value: iv,
 				},
-			}
+			})
 		}
 	}
-	close(ch)
-	return nil
+	return result, nil
 }
 
-func (s *store) ListInnerElement(_ context.Context, key storage.IDs, ch chan<- InnerEntry) error {
+func (s *store) ListInnerElement(_ context.Context, key storage.IDs) ([]InnerEntry, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	if _, ok := s.storage[key]; !ok {
-		return errors.NewNotFound("no inner map in storage stored")
+		return nil, errors.NewNotFound("no inner map in storage stored")
 	}
+	result := make([]InnerEntry, 0, len(s.storage[key].Value))
 	for k, v := range s.storage[key].Value {
-		ch <- InnerEntry{
+		result = append(result, InnerEntry{
 			Key:   k,
 			Value: v,
-		}
+		})
 	}
-	close(ch)
-	return nil
+	return result, nil
 }
 
 func (s *store) DeleteInnerElement(_ context.Context, key storage.IDs, innerKey storage.IDs) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if _, ok := s.storage[key]; !ok {
+		return errors.NewNotFound("inner map does not exist")
+	}
 	delete(s.storage[key].Value, innerKey)
+	delete(s.frozen[key], innerKey)
+	s.persist(key)
+	return nil
+}
+
+func (s *store) FreezeInnerElement(_ context.Context, key storage.IDs, innerKey storage.IDs, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.frozen[key]; !ok {
+		s.frozen[key] = make(map[storage.IDs]time.Time)
+	}
+	s.frozen[key][innerKey] = until
+	entry := FrozenEntry{
+		Key:      key,
+		InnerKey: innerKey,
+		Until:    until,
+	}
+	s.watchers.Send(event.Event{
+		Key:   key,
+		Value: entry,
+		Type:  storage.Frozen,
+	})
 	return nil
 }
+
+func (s *store) IsInnerElementFrozen(_ context.Context, key storage.IDs, innerKey storage.IDs) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	until, ok := s.frozen[key][innerKey]
+	return ok && time.Now().Before(until)
+}
+
+func (s *store) ListFrozenElements(_ context.Context) ([]FrozenEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	result := make([]FrozenEntry, 0)
+	for k, v := range s.frozen {
+		for ik, until := range v {
+			if !now.Before(until) {
+				delete(v, ik)
+				continue
+			}
+			result = append(result, FrozenEntry{
+				Key:      k,
+				InnerKey: ik,
+				Until:    until,
+			})
+		}
+		if len(v) == 0 {
+			delete(s.frozen, k)
+		}
+	}
+	return result, nil
+}
+
+func (s *store) ListViolations(_ context.Context, maxSum int) ([]Violation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]Violation, 0)
+
+	// inner keys only have PLMN ID and cell ID
+	keys := make(map[storage.IDs]storage.IDs)
+	for k := range s.storage {
+		keys[storage.IDs{PlmnID: k.PlmnID, CellID: k.CellID}] = k
+	}
+
+	type pair struct {
+		a storage.IDs
+		b storage.IDs
+	}
+	visited := make(map[pair]bool)
+	for k, v := range s.storage {
+		for ik, ocn := range v.Value {
+			rk, ok := keys[storage.IDs{PlmnID: ik.PlmnID, CellID: ik.CellID}]
+			if !ok {
+				continue
+			}
+			reverseOcn, ok := s.storage[rk].Value[storage.IDs{PlmnID: k.PlmnID, CellID: k.CellID}]
+			if !ok || visited[pair{a: rk, b: k}] {
+				continue
+			}
+			visited[pair{a: k, b: rk}] = true
+			sum := int(ocn-meastype.QOffset0dB) + int(reverseOcn-meastype.QOffset0dB)
+			if sum > maxSum || sum < -maxSum {
+				result = append(result, Violation{
+					Key:        k,
+					InnerKey:   ik,
+					Ocn:        ocn,
+					ReverseOcn: reverseOcn,
+				})
+			}
+		}
+	}
+	return result, nil
+}
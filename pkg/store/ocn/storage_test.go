@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocnstorage
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/onosproject/onos-mlb/pkg/store/event"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/onosproject/onos-mlb/pkg/store/watcher"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreCopies(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	ch := make(chan event.Event, 10)
+	assert.NoError(t, s.Watch(ctx, ch, watcher.WithTypeFilter(storage.Created, storage.Updated)))
+
+	// the value put is not shared with the caller
+	value := &OcnMap{Value: map[storage.IDs]meastype.QOffsetRange{neighborB: meastype.QOffset0dB}}
+	_, err := s.Put(ctx, cellA, value)
+	assert.NoError(t, err)
+	value.Value[neighborB] = meastype.QOffset1dB
+	assertOcns(t, s, map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{cellA: {neighborB: meastype.QOffset0dB}})
+
+	// neither is the value got
+	entry, err := s.Get(ctx, cellA)
+	assert.NoError(t, err)
+	entry.Value.Value[neighborB] = meastype.QOffset2dB
+	assertOcns(t, s, map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{cellA: {neighborB: meastype.QOffset0dB}})
+
+	// nor the value updated
+	assert.NoError(t, s.Update(ctx, entry))
+	entry.Value.Value[neighborB] = meastype.QOffset3dB
+	assertOcns(t, s, map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{cellA: {neighborB: meastype.QOffset2dB}})
+
+	// the events have their own copies as well
+	assert.NoError(t, s.PutInnerMapElem(ctx, cellA, neighborB, meastype.QOffset4dB))
+	for _, expected := range []meastype.QOffsetRange{meastype.QOffset0dB, meastype.QOffset2dB} {
+		e := <-ch
+		assert.Equal(t, expected, e.Value.(*OcnMap).Value[neighborB])
+	}
+}
+
+func TestStoreGetWhileWriting(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, NewStore())
+
+	// run with -race to see that the readers do not share the maps with the writer
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			_ = s.PutInnerMapElem(ctx, cellA, neighborB, meastype.QOffsetRange(i%10))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			entry, err := s.Get(ctx, cellA)
+			assert.NoError(t, err)
+			for range entry.Value.Value {
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocnstorage
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+)
+
+// Txn stages the Ocns of several cells so that they are put into the store all at once
+type Txn interface {
+	// PutInnerMapElems stages the Ocns to be put into the inner map of the key
+	PutInnerMapElems(key storage.IDs, ocns map[storage.IDs]meastype.QOffsetRange)
+
+	// Keys returns the keys whose Ocns are staged
+	Keys() []storage.IDs
+
+	// Commit puts all staged Ocns into the store at once; if the inner map of any staged key does not exist,
+	// none of them is put. The committed Ocns are written into the backend on the next flush.
+	Commit(ctx context.Context) error
+}
+
+type txn struct {
+	store  *store
+	staged map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+}
+
+func (s *store) Begin(_ context.Context) Txn {
+	return &txn{
+		store:  s,
+		staged: make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange),
+	}
+}
+
+func (t *txn) PutInnerMapElems(key storage.IDs, ocns map[storage.IDs]meastype.QOffsetRange) {
+	if _, ok := t.staged[key]; !ok {
+		t.staged[key] = make(map[storage.IDs]meastype.QOffsetRange)
+	}
+	for k, v := range ocns {
+		t.staged[key][k] = v
+	}
+}
+
+func (t *txn) Keys() []storage.IDs {
+	keys := make([]storage.IDs, 0, len(t.staged))
+	for key := range t.staged {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (t *txn) Commit(_ context.Context) error {
+	s := t.store
+	s.mu.Lock()
+	for key := range t.staged {
+		if _, ok := s.storage[key]; !ok {
+			s.mu.Unlock()
+			return errors.NewNotFound("inner map of %v does not exist - the transaction is aborted", key)
+		}
+	}
+	for key, ocns := range t.staged {
+		for k, v := range ocns {
+			s.storage[key].Value[k] = v
+		}
+		s.persist(key)
+	}
+	t.staged = make(map[storage.IDs]map[storage.IDs]meastype.QOffsetRange)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocnstorage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/backend"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	meastype "github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	cellA = storage.IDs{NodeID: "e2:1", PlmnID: "138426", CellID: "a"}
+	cellB = storage.IDs{NodeID: "e2:2", PlmnID: "138426", CellID: "b"}
+	cellC = storage.IDs{NodeID: "e2:3", PlmnID: "138426", CellID: "c"}
+
+	neighborA = storage.IDs{PlmnID: "138426", CellID: "a"}
+	neighborB = storage.IDs{PlmnID: "138426", CellID: "b"}
+)
+
+// newTestStore generates the store with the Ocn maps of cells a and b
+func newTestStore(t *testing.T, s Store) Store {
+	ctx := context.Background()
+	_, err := s.Put(ctx, cellA, &OcnMap{Value: map[storage.IDs]meastype.QOffsetRange{neighborB: meastype.QOffset0dB}})
+	assert.NoError(t, err)
+	_, err = s.Put(ctx, cellB, &OcnMap{Value: map[storage.IDs]meastype.QOffsetRange{neighborA: meastype.QOffset0dB}})
+	assert.NoError(t, err)
+	return s
+}
+
+func TestTxnCommit(t *testing.T) {
+	tests := []struct {
+		name     string
+		staged   map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+		notFound bool
+		expected map[storage.IDs]map[storage.IDs]meastype.QOffsetRange
+	}{
+		{
+			name: "all staged Ocns are put",
+			staged: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset3dB},
+				cellB: {neighborA: meastype.QOffsetMinus3dB},
+			},
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset3dB},
+				cellB: {neighborA: meastype.QOffsetMinus3dB},
+			},
+		},
+		{
+			name: "new neighbor is added to the inner map",
+			staged: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborA: meastype.QOffset1dB},
+			},
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset0dB, neighborA: meastype.QOffset1dB},
+				cellB: {neighborA: meastype.QOffset0dB},
+			},
+		},
+		{
+			name: "nothing is put if an inner map does not exist",
+			staged: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset3dB},
+				cellC: {neighborA: meastype.QOffset3dB},
+			},
+			notFound: true,
+			expected: map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+				cellA: {neighborB: meastype.QOffset0dB},
+				cellB: {neighborA: meastype.QOffset0dB},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestStore(t, NewStore())
+			txn := s.Begin(ctx)
+			for key, ocns := range test.staged {
+				txn.PutInnerMapElems(key, ocns)
+			}
+			assert.ElementsMatch(t, keysOf(test.staged), txn.Keys())
+
+			err := txn.Commit(ctx)
+			if test.notFound {
+				assert.True(t, errors.IsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assertOcns(t, s, test.expected)
+		})
+	}
+}
+
+func TestTxnFlush(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ocn.json")
+	b, err := backend.NewFileBackend(path)
+	assert.NoError(t, err)
+	s, err := NewStoreWithBackend(b)
+	assert.NoError(t, err)
+	newTestStore(t, s)
+
+	txn := s.Begin(ctx)
+	txn.PutInnerMapElems(cellA, map[storage.IDs]meastype.QOffsetRange{neighborB: meastype.QOffset3dB})
+	assert.NoError(t, txn.Commit(ctx))
+	assert.NoError(t, s.Flush(ctx))
+
+	// the committed Ocns are reloaded from the backend
+	b, err = backend.NewFileBackend(path)
+	assert.NoError(t, err)
+	reloaded, err := NewStoreWithBackend(b)
+	assert.NoError(t, err)
+	assertOcns(t, reloaded, map[storage.IDs]map[storage.IDs]meastype.QOffsetRange{
+		cellA: {neighborB: meastype.QOffset3dB},
+		cellB: {neighborA: meastype.QOffset0dB},
+	})
+}
+
+func keysOf(m map[storage.IDs]map[storage.IDs]meastype.QOffsetRange) []storage.IDs {
+	keys := make([]storage.IDs, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func assertOcns(t *testing.T, s Store, expected map[storage.IDs]map[storage.IDs]meastype.QOffsetRange) {
+	ctx := context.Background()
+	for key, ocns := range expected {
+		entry, err := s.Get(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, ocns, entry.Value.Value, "Ocns of %v", key)
+	}
+}
@@ -5,6 +5,8 @@
 package ocnstorage
 
 import (
+	"time"
+
 	"github.com/onosproject/onos-mlb/pkg/store/storage"
 	"github.com/onosproject/rrm-son-lib/pkg/model/measurement/type"
 )
@@ -25,3 +27,18 @@ type InnerEntry struct {
 type OcnMap struct {
 	Value map[storage.IDs]meastype.QOffsetRange
 }
+
+// FrozenEntry is an entry of the inner store element whose Ocn must not be changed until the given time
+type FrozenEntry struct {
+	Key      storage.IDs
+	InnerKey storage.IDs
+	Until    time.Time
+}
+
+// Violation is a neighbor pair whose Ocns are not consistent on both sides
+type Violation struct {
+	Key        storage.IDs
+	InnerKey   storage.IDs
+	Ocn        meastype.QOffsetRange
+	ReverseOcn meastype.QOffsetRange
+}
@@ -6,17 +6,46 @@ package paramstorage
 
 import (
 	"context"
-	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"encoding/json"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-mlb/pkg/store/backend"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
 	"sync"
 )
 
-// NewStore generates a store object to save parameters into a map
+var log = logging.GetLogger()
+
+// NewStore generates a store object to save parameters into a map in memory
 func NewStore() Store {
 	return &store{
-		storage: make(map[string]int),
+		storage: storage.NewStore[string, int](),
 	}
 }
 
+// NewStoreWithBackend generates a store object which reloads the parameters persisted in the backend
+// and persists every change of them
+func NewStoreWithBackend(b backend.Backend) (Store, error) {
+	s := &store{
+		storage: storage.NewStore[string, int](),
+		backend: b,
+	}
+	elements, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range elements {
+		var v int
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		if _, err := s.storage.Put(context.Background(), key, v); err != nil {
+			return nil, err
+		}
+	}
+	log.Infof("Reloaded %v parameters", len(elements))
+	return s, nil
+}
+
 // Store includes all functions for parameter storage
 type Store interface {
 	// Put puts parameter key and value
@@ -27,32 +56,59 @@ type Store interface {
 
 	// Update updates parameter value with key
 	Update(ctx context.Context, key string, value int) error
+
+	// List gets all parameters
+	List(ctx context.Context) (map[string]int, error)
 }
 
 type store struct {
-	storage map[string]int
-	mu      sync.RWMutex
+	storage storage.Store[string, int]
+	// mu keeps the backend in the order of the changes
+	mu      sync.Mutex
+	backend backend.Backend
 }
 
-func (s *store) Put(_ context.Context, key string, value int) error {
+func (s *store) Put(ctx context.Context, key string, value int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.storage[key] = value
-	return nil
+	if _, err := s.storage.Put(ctx, key, value); err != nil {
+		return err
+	}
+	return s.persist(key, value)
 }
 
-func (s *store) Get(_ context.Context, key string) (int, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if _, ok := s.storage[key]; !ok {
-		return 0, errors.NewNotFound("key not found")
+func (s *store) Get(ctx context.Context, key string) (int, error) {
+	entry, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return 0, err
 	}
-	return s.storage[key], nil
+	return entry.Value, nil
 }
 
-func (s *store) Update(_ context.Context, key string, value int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.storage[key] = value
-	return nil
+func (s *store) Update(ctx context.Context, key string, value int) error {
+	return s.Put(ctx, key, value)
+}
+
+func (s *store) List(ctx context.Context) (map[string]int, error) {
+	entries, err := s.storage.ListElements(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int, len(entries))
+	for _, e := range entries {
+		result[e.Key] = e.Value
+	}
+	return result, nil
+}
+
+// persist writes the parameter into the backend; it has to be called with the lock held
+func (s *store) persist(key string, value int) error {
+	if s.backend == nil {
+		return nil
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.backend.Put(key, bytes)
 }
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ListOptions is the set of options to list the elements of a store
+type ListOptions[K comparable, V any] struct {
+	filter func(key K, value V) bool
+	cursor func(key K) string
+	after  *K
+	limit  int
+}
+
+// ListOption is an option to list the elements of a store
+type ListOption[K comparable, V any] func(options *ListOptions[K, V])
+
+// WithFilter lists only the elements for which the filter returns true
+func WithFilter[K comparable, V any](filter func(key K, value V) bool) ListOption[K, V] {
+	return func(options *ListOptions[K, V]) {
+		options.filter = filter
+	}
+}
+
+// WithCursor lists only the elements after the key, which is the last key of the previous page
+func WithCursor[K comparable, V any](after K) ListOption[K, V] {
+	return func(options *ListOptions[K, V]) {
+		options.after = &after
+	}
+}
+
+// WithKeyCursor orders the elements by the cursor of their keys, which must differ for different keys
+func WithKeyCursor[K comparable, V any](cursor func(key K) string) ListOption[K, V] {
+	return func(options *ListOptions[K, V]) {
+		options.cursor = cursor
+	}
+}
+
+// WithLimit lists at most limit elements
+func WithLimit[K comparable, V any](limit int) ListOption[K, V] {
+	return func(options *ListOptions[K, V]) {
+		options.limit = limit
+	}
+}
+
+// Select applies the options to the snapshot of elements. The elements are in no particular order
+// unless a cursor or a limit is given; then they are ordered by the cursor of their keys
+// so that the next page starts after the last key of the previous page.
+func Select[K comparable, V any](entries []*Entry[K, V], opts ...ListOption[K, V]) []*Entry[K, V] {
+	options := &ListOptions[K, V]{
+		cursor: keyCursor[K],
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	result := make([]*Entry[K, V], 0, len(entries))
+	for _, e := range entries {
+		if options.filter == nil || options.filter(e.Key, e.Value) {
+			result = append(result, e)
+		}
+	}
+	if options.after == nil && options.limit <= 0 {
+		return result
+	}
+
+	keys := make(map[K]string, len(result))
+	for _, e := range result {
+		keys[e.Key] = options.cursor(e.Key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return keys[result[i].Key] < keys[result[j].Key]
+	})
+	if options.after != nil {
+		after := options.cursor(*options.after)
+		start := sort.Search(len(result), func(i int) bool {
+			return keys[result[i].Key] > after
+		})
+		result = result[start:]
+	}
+	if options.limit > 0 && len(result) > options.limit {
+		result = result[:options.limit]
+	}
+	return result
+}
+
+// keyCursor is the default cursor of a key: a string key itself, the String() of a key which has it,
+// or else the Go syntax of the key, which tells apart the fields of a struct key
+func keyCursor[K comparable](key K) string {
+	switch k := any(key).(type) {
+	case string:
+		return k
+	case fmt.Stringer:
+		return k.String()
+	default:
+		return fmt.Sprintf("%#v", key)
+	}
+}
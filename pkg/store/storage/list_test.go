@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelect(t *testing.T) {
+	even := func(_ string, value int) bool {
+		return value%2 == 0
+	}
+	tests := []struct {
+		name     string
+		opts     []ListOption[string, int]
+		expected []string
+		ordered  bool
+	}{
+		{
+			name:     "all",
+			expected: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:     "filter",
+			opts:     []ListOption[string, int]{WithFilter(even)},
+			expected: []string{"b", "d"},
+		},
+		{
+			name:     "limit",
+			opts:     []ListOption[string, int]{WithLimit[string, int](2)},
+			expected: []string{"a", "b"},
+			ordered:  true,
+		},
+		{
+			name:     "cursor",
+			opts:     []ListOption[string, int]{WithCursor[string, int]("b")},
+			expected: []string{"c", "d", "e"},
+			ordered:  true,
+		},
+		{
+			name:     "cursor and limit",
+			opts:     []ListOption[string, int]{WithCursor[string, int]("b"), WithLimit[string, int](2)},
+			expected: []string{"c", "d"},
+			ordered:  true,
+		},
+		{
+			name:     "cursor of a deleted key",
+			opts:     []ListOption[string, int]{WithCursor[string, int]("bb")},
+			expected: []string{"c", "d", "e"},
+			ordered:  true,
+		},
+		{
+			name:     "cursor after the last key",
+			opts:     []ListOption[string, int]{WithCursor[string, int]("e")},
+			expected: []string{},
+			ordered:  true,
+		},
+		{
+			name:     "filter and limit",
+			opts:     []ListOption[string, int]{WithFilter(even), WithLimit[string, int](1)},
+			expected: []string{"b"},
+			ordered:  true,
+		},
+		{
+			name:     "limit larger than the elements",
+			opts:     []ListOption[string, int]{WithLimit[string, int](10)},
+			expected: []string{"a", "b", "c", "d", "e"},
+			ordered:  true,
+		},
+	}
+
+	// in no particular order
+	entries := []*Entry[string, int]{
+		{Key: "d", Value: 4},
+		{Key: "a", Value: 1},
+		{Key: "e", Value: 5},
+		{Key: "c", Value: 3},
+		{Key: "b", Value: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Select(entries, test.opts...)
+			keys := make([]string, 0, len(result))
+			for _, e := range result {
+				keys = append(keys, e.Key)
+			}
+			if test.ordered {
+				assert.Equal(t, test.expected, keys)
+			} else {
+				assert.ElementsMatch(t, test.expected, keys)
+			}
+		})
+	}
+}
+
+func TestListKeysPages(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore[IDs, int]()
+	for i, cellID := range []string{"5", "3", "1", "4", "2"} {
+		_, err := s.Put(ctx, IDs{PlmnID: "138426", CellID: cellID}, i)
+		assert.NoError(t, err)
+	}
+
+	pages := make([][]string, 0)
+	opts := []ListOption[IDs, int]{WithLimit[IDs, int](2)}
+	for {
+		keys, err := s.ListKeys(ctx, opts...)
+		assert.NoError(t, err)
+		if len(keys) == 0 {
+			break
+		}
+		page := make([]string, 0, len(keys))
+		for _, key := range keys {
+			page = append(page, key.CellID)
+		}
+		pages = append(pages, page)
+		opts = []ListOption[IDs, int]{WithCursor[IDs, int](keys[len(keys)-1]), WithLimit[IDs, int](2)}
+	}
+	assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}, {"5"}}, pages)
+}
+
+func TestListKeysCursor(t *testing.T) {
+	ctx := context.Background()
+
+	// the keys print the same with %v but their fields differ
+	s := NewStore[IDs, int]()
+	first := IDs{PlmnID: "1 2", CellID: "3"}
+	second := IDs{PlmnID: "1", CellID: "2 3"}
+	for _, key := range []IDs{first, second} {
+		_, err := s.Put(ctx, key, 0)
+		assert.NoError(t, err)
+	}
+	keys, err := s.ListKeys(ctx, WithLimit[IDs, int](1))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	next, err := s.ListKeys(ctx, WithCursor[IDs, int](keys[0]), WithLimit[IDs, int](1))
+	assert.NoError(t, err)
+	assert.Len(t, next, 1)
+	assert.ElementsMatch(t, []IDs{first, second}, append(keys, next...))
+
+	// a key cursor orders the keys numerically
+	n := NewStore[int, int]()
+	for _, key := range []int{9, 10, 1} {
+		_, err := n.Put(ctx, key, 0)
+		assert.NoError(t, err)
+	}
+	padded := WithKeyCursor[int, int](func(key int) string {
+		return fmt.Sprintf("%08d", key)
+	})
+	ints, err := n.ListKeys(ctx, padded, WithLimit[int, int](2))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 9}, ints)
+	ints, err = n.ListKeys(ctx, padded, WithCursor[int, int](9))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10}, ints)
+}
+
+type testStringer struct {
+	id int
+}
+
+func (s testStringer) String() string {
+	return fmt.Sprintf("key-%d", s.id)
+}
+
+func TestKeyCursor(t *testing.T) {
+	assert.Equal(t, "a", keyCursor("a"))
+	assert.Equal(t, "key-1", keyCursor(testStringer{id: 1}))
+	assert.NotEqual(t, keyCursor(IDs{PlmnID: "1 2", CellID: "3"}), keyCursor(IDs{PlmnID: "1", CellID: "2 3"}))
+}
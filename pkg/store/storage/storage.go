@@ -8,7 +8,6 @@ import (
 	"context"
 	"sync"
 
-	"github.com/google/uuid"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-mlb/pkg/store/event"
@@ -17,54 +16,61 @@ import (
 
 var log = logging.GetLogger()
 
-var _ Store = &store{} // to check interface and struct in compile time (static check)
+var _ Store[IDs, Measurement] = &store[IDs, Measurement]{} // to check interface and struct in compile time (static check)
 
-// NewStore generates the new store
-func NewStore() Store {
+// MeasurementStore is the store of a measurement of each cell
+type MeasurementStore = Store[IDs, Measurement]
+
+// NeighborStore is the store of the neighbor list of each cell
+type NeighborStore = Store[IDs, []IDs]
+
+// NewStore generates the new store of values of type V with keys of type K
+func NewStore[K comparable, V any]() Store[K, V] {
 	watchers := watcher.NewWatchers()
-	return &store{
-		storage:  make(map[IDs]*Entry),
+	return &store[K, V]{
+		storage:  make(map[K]*Entry[K, V]),
 		watchers: watchers,
 	}
 }
 
 // Store has all functions in this store
-type Store interface {
+type Store[K comparable, V any] interface {
 	// Put puts key and its value
-	Put(ctx context.Context, key IDs, value interface{}) (*Entry, error)
+	Put(ctx context.Context, key K, value V) (*Entry[K, V], error)
 
 	// Get gets the element with key
-	Get(ctx context.Context, key IDs) (*Entry, error)
+	Get(ctx context.Context, key K) (*Entry[K, V], error)
 
-	// ListElements gets all elements in this store
-	ListElements(ctx context.Context, ch chan<- *Entry) error
+	// ListElements gets a copy of the elements in this store which match the options;
+	// the store is not locked while the caller handles them
+	ListElements(ctx context.Context, opts ...ListOption[K, V]) ([]*Entry[K, V], error)
 
-	// ListKeys gets all keys in this store
-	ListKeys(ctx context.Context, ch chan<- IDs) error
+	// ListKeys gets the keys of the elements in this store which match the options
+	ListKeys(ctx context.Context, opts ...ListOption[K, V]) ([]K, error)
 
 	// Update updates an element
-	Update(ctx context.Context, entry *Entry) error
+	Update(ctx context.Context, entry *Entry[K, V]) error
 
 	// Delete deletes an element
-	Delete(ctx context.Context, key IDs) error
+	Delete(ctx context.Context, key K) error
 
-	// Watch watches the event of this store
-	Watch(ctx context.Context, ch chan<- event.Event) error
+	// Watch watches the event of this store until the context is done; the channel is closed when the watch ends
+	Watch(ctx context.Context, ch chan<- event.Event, opts ...watcher.Option) error
 
 	// Print prints the map in this store for debugging
 	Print()
 }
 
-type store struct {
-	storage  map[IDs]*Entry
+type store[K comparable, V any] struct {
+	storage  map[K]*Entry[K, V]
 	mu       sync.RWMutex
 	watchers *watcher.Watchers
 }
 
-func (s *store) Put(_ context.Context, key IDs, value interface{}) (*Entry, error) {
+func (s *store[K, V]) Put(_ context.Context, key K, value V) (*Entry[K, V], error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	entry := &Entry{
+	entry := &Entry[K, V]{
 		Key:   key,
 		Value: value,
 	}
@@ -77,7 +83,7 @@ func (s *store) Put(_ context.Context, key IDs, value interface{}) (*Entry, erro
 	return entry, nil
 }
 
-func (s *store) Get(_ context.Context, key IDs) (*Entry, error) {
+func (s *store[K, V]) Get(_ context.Context, key K) (*Entry[K, V], error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	if v, ok := s.storage[key]; ok {
@@ -86,35 +92,30 @@ func (s *store) Get(_ context.Context, key IDs) (*Entry, error) {
 	return nil, errors.New(errors.NotFound, "the storage entry does not exist")
 }
 
-func (s *store) ListElements(_ context.Context, ch chan<- *Entry) error {
+func (s *store[K, V]) ListElements(_ context.Context, opts ...ListOption[K, V]) ([]*Entry[K, V], error) {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if len(s.storage) == 0 {
-		return errors.New(errors.NotFound, "no storage entries stored")
-	}
-
+	entries := make([]*Entry[K, V], 0, len(s.storage))
 	for _, entry := range s.storage {
-		ch <- entry
+		e := *entry
+		entries = append(entries, &e)
 	}
-	close(ch)
-	return nil
+	s.mu.RUnlock()
+	return Select(entries, opts...), nil
 }
 
-func (s *store) ListKeys(_ context.Context, ch chan<- IDs) error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if len(s.storage) == 0 {
-		return errors.New(errors.NotFound, "no storage entries stored")
+func (s *store[K, V]) ListKeys(ctx context.Context, opts ...ListOption[K, V]) ([]K, error) {
+	entries, err := s.ListElements(ctx, opts...)
+	if err != nil {
+		return nil, err
 	}
-
-	for key := range s.storage {
-		ch <- key
+	keys := make([]K, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
 	}
-	close(ch)
-	return nil
+	return keys, nil
 }
 
-func (s *store) Update(_ context.Context, entry *Entry) error {
+func (s *store[K, V]) Update(_ context.Context, entry *Entry[K, V]) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if _, ok := s.storage[entry.Key]; ok {
@@ -124,38 +125,49 @@ func (s *store) Update(_ context.Context, entry *Entry) error {
 			Value: entry,
 			Type:  Updated,
 		})
+		return nil
 	}
 
-	return errors.New(errors.NotFound, "no storage entry does not exist; put the entry first")
+	return errors.New(errors.NotFound, "the storage entry does not exist; put the entry first")
 }
 
-func (s *store) Delete(_ context.Context, key IDs) error {
+func (s *store[K, V]) Delete(_ context.Context, key K) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	entry, ok := s.storage[key]
+	if !ok {
+		return nil
+	}
 	delete(s.storage, key)
+	s.watchers.Send(event.Event{
+		Key:   key,
+		Value: entry,
+		Type:  Deleted,
+	})
 	return nil
 }
 
-func (s *store) Watch(ctx context.Context, ch chan<- event.Event) error {
-	id := uuid.New()
-	err := s.watchers.AddWatcher(id, ch)
-	if err != nil {
-		log.Error(err)
-		close(ch)
-		return err
+func (s *store[K, V]) Watch(ctx context.Context, ch chan<- event.Event, opts ...watcher.Option) error {
+	// the read lock keeps the events from being sent between the replay and the registration
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watchers.Watch(ctx, ch, s.replay, opts...)
+}
+
+func (s *store[K, V]) replay() []event.Event {
+	events := make([]event.Event, 0, len(s.storage))
+	for key, entry := range s.storage {
+		e := *entry
+		events = append(events, event.Event{
+			Key:   key,
+			Value: &e,
+			Type:  Created,
+		})
 	}
-	go func() {
-		<-ctx.Done()
-		err = s.watchers.RemoveWatcher(id)
-		if err != nil {
-			log.Error(err)
-		}
-		close(ch)
-	}()
-	return nil
+	return events
 }
 
-func (s *store) Print() {
+func (s *store[K, V]) Print() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for k, v := range s.storage {
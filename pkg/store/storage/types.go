@@ -4,6 +4,8 @@
 
 package storage
 
+import "time"
+
 // IDs is a key of this store element
 type IDs struct {
 	NodeID    string
@@ -13,11 +15,17 @@ type IDs struct {
 }
 
 // Entry is an entry of this store element
-type Entry struct {
-	Key   IDs
-	Value interface{}
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
 }
 
+// MeasurementEntry is an entry of the measurement store
+type MeasurementEntry = Entry[IDs, Measurement]
+
+// NeighborEntry is an entry of the neighbor store
+type NeighborEntry = Entry[IDs, []IDs]
+
 type storageEvent int
 
 const (
@@ -32,16 +40,26 @@ const (
 
 	// Deleted means that a store element is deleted
 	Deleted
+
+	// Frozen means that a store element is frozen and must not be changed for a while
+	Frozen
 )
 
 // String returns string value of storageEvent enum value
 func (e storageEvent) String() string {
-	return [...]string{"None", "Created", "Updated", "Deleted"}[e]
+	return [...]string{"None", "Created", "Updated", "Deleted", "Frozen"}[e]
 }
 
 // Measurement is the struct to store measurement results
 type Measurement struct {
 	Value int
+	// Timestamp is the time the measurement was reported
+	Timestamp time.Time
+}
+
+// IsOlderThan returns true if the measurement is older than the max age at now; zero max age means no limit
+func (m Measurement) IsOlderThan(now time.Time, maxAge time.Duration) bool {
+	return maxAge > 0 && now.Sub(m.Timestamp) > maxAge
 }
 
 // Statistics is the struct to store statistics
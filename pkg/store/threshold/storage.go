@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package thresholdstorage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// NewStore generates a store object to save threshold overrides
+func NewStore() Store {
+	return &store{}
+}
+
+// Store includes all functions for threshold override storage
+type Store interface {
+	// SetOverrides replaces the threshold profiles and their bindings
+	SetOverrides(ctx context.Context, overrides Overrides) error
+
+	// GetOverrides gets the threshold profiles and their bindings
+	GetOverrides(ctx context.Context) (Overrides, error)
+
+	// Resolve returns the effective profile of the cell and the scope it comes from
+	Resolve(ctx context.Context, ids storage.IDs, global Profile) (Profile, string)
+}
+
+type store struct {
+	overrides Overrides
+	mu        sync.RWMutex
+}
+
+func (s *store) SetOverrides(_ context.Context, overrides Overrides) error {
+	if err := overrides.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = overrides
+	return nil
+}
+
+func (s *store) GetOverrides(_ context.Context) (Overrides, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.overrides, nil
+}
+
+func (s *store) Resolve(_ context.Context, ids storage.IDs, global Profile) (Profile, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.overrides.Resolve(ids, global)
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package thresholdstorage
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+const (
+	// ScopeGlobal means that the thresholds come from the global parameters
+	ScopeGlobal = "global"
+
+	// ScopeClass means that the thresholds come from the profile bound to the cell class
+	ScopeClass = "class"
+
+	// ScopeNode means that the thresholds come from the profile bound to the E2 node
+	ScopeNode = "node"
+
+	// ScopeCell means that the thresholds come from the profile bound to the cell
+	ScopeCell = "cell"
+)
+
+// Profile is a named set of thresholds in percent
+type Profile struct {
+	TargetThreshold   int `json:"target_threshold"`
+	OverloadThreshold int `json:"overload_threshold"`
+}
+
+// CellBinding binds a profile to a cell
+type CellBinding struct {
+	PlmnID  string `json:"plmn_id"`
+	CellID  string `json:"cell_id"`
+	Profile string `json:"profile"`
+}
+
+// NodeBinding binds a profile to all cells of an E2 node
+type NodeBinding struct {
+	NodeID  string `json:"node_id"`
+	Profile string `json:"profile"`
+}
+
+// ClassBinding binds a profile to all cells of a cell class
+type ClassBinding struct {
+	Class   string `json:"class"`
+	Profile string `json:"profile"`
+}
+
+// ClassMember assigns a cell to a cell class such as macro or small
+type ClassMember struct {
+	PlmnID string `json:"plmn_id"`
+	CellID string `json:"cell_id"`
+	Class  string `json:"class"`
+}
+
+// Overrides is the set of threshold profiles and their bindings.
+// The profile bound to the cell overrides the one bound to its E2 node,
+// which overrides the one bound to its class; cells without any binding use the global thresholds.
+type Overrides struct {
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	Cells    []CellBinding      `json:"cells,omitempty"`
+	Nodes    []NodeBinding      `json:"nodes,omitempty"`
+	Classes  []ClassBinding     `json:"classes,omitempty"`
+	Members  []ClassMember      `json:"members,omitempty"`
+}
+
+// Validate checks the thresholds of each profile and that each binding refers to a profile
+func (o Overrides) Validate() error {
+	for name, p := range o.Profiles {
+		if p.TargetThreshold < 0 || p.TargetThreshold > 100 || p.OverloadThreshold < 0 || p.OverloadThreshold > 100 {
+			return errors.NewInvalid("thresholds of profile %s should be between 0 and 100", name)
+		}
+		if p.TargetThreshold > p.OverloadThreshold {
+			return errors.NewInvalid("target threshold of profile %s should not be larger than its overload threshold", name)
+		}
+	}
+	for _, b := range o.Cells {
+		if _, ok := o.Profiles[b.Profile]; !ok {
+			return errors.NewInvalid("cell %s:%s is bound to unknown profile %s", b.PlmnID, b.CellID, b.Profile)
+		}
+	}
+	for _, b := range o.Nodes {
+		if _, ok := o.Profiles[b.Profile]; !ok {
+			return errors.NewInvalid("E2 node %s is bound to unknown profile %s", b.NodeID, b.Profile)
+		}
+	}
+	for _, b := range o.Classes {
+		if _, ok := o.Profiles[b.Profile]; !ok {
+			return errors.NewInvalid("class %s is bound to unknown profile %s", b.Class, b.Profile)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the effective profile of the cell and the scope it comes from;
+// the node ID of the cell may be empty for the neighbor cells not controlled by this app
+func (o Overrides) Resolve(ids storage.IDs, global Profile) (Profile, string) {
+	for _, b := range o.Cells {
+		if b.PlmnID == ids.PlmnID && b.CellID == ids.CellID {
+			return o.Profiles[b.Profile], ScopeCell
+		}
+	}
+	if ids.NodeID != "" {
+		for _, b := range o.Nodes {
+			if b.NodeID == ids.NodeID {
+				return o.Profiles[b.Profile], ScopeNode
+			}
+		}
+	}
+	for _, m := range o.Members {
+		if m.PlmnID != ids.PlmnID || m.CellID != ids.CellID {
+			continue
+		}
+		for _, b := range o.Classes {
+			if b.Class == m.Class {
+				return o.Profiles[b.Profile], ScopeClass
+			}
+		}
+	}
+	return global, ScopeGlobal
+}
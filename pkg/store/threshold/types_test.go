@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package thresholdstorage
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverridesResolve(t *testing.T) {
+	global := Profile{TargetThreshold: 30, OverloadThreshold: 70}
+	overrides := Overrides{
+		Profiles: map[string]Profile{
+			"cell":  {TargetThreshold: 10, OverloadThreshold: 50},
+			"node":  {TargetThreshold: 20, OverloadThreshold: 60},
+			"small": {TargetThreshold: 40, OverloadThreshold: 80},
+		},
+		Cells: []CellBinding{
+			{PlmnID: "138426", CellID: "c1", Profile: "cell"},
+		},
+		Nodes: []NodeBinding{
+			{NodeID: "e2:1", Profile: "node"},
+		},
+		Classes: []ClassBinding{
+			{Class: "small", Profile: "small"},
+		},
+		Members: []ClassMember{
+			{PlmnID: "138426", CellID: "c1", Class: "small"},
+			{PlmnID: "138426", CellID: "c2", Class: "small"},
+			{PlmnID: "138426", CellID: "c3", Class: "small"},
+			{PlmnID: "138426", CellID: "c4", Class: "macro"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		ids      storage.IDs
+		expected Profile
+		scope    string
+	}{
+		{
+			name:     "cell wins over E2 node and class",
+			ids:      storage.IDs{NodeID: "e2:1", PlmnID: "138426", CellID: "c1"},
+			expected: overrides.Profiles["cell"],
+			scope:    ScopeCell,
+		},
+		{
+			name:     "E2 node wins over class",
+			ids:      storage.IDs{NodeID: "e2:1", PlmnID: "138426", CellID: "c2"},
+			expected: overrides.Profiles["node"],
+			scope:    ScopeNode,
+		},
+		{
+			name:     "class",
+			ids:      storage.IDs{NodeID: "e2:2", PlmnID: "138426", CellID: "c3"},
+			expected: overrides.Profiles["small"],
+			scope:    ScopeClass,
+		},
+		{
+			name:     "neighbor without E2 node ID resolves by its class",
+			ids:      storage.IDs{PlmnID: "138426", CellID: "c2"},
+			expected: overrides.Profiles["small"],
+			scope:    ScopeClass,
+		},
+		{
+			name:     "class without profile falls back to global",
+			ids:      storage.IDs{NodeID: "e2:2", PlmnID: "138426", CellID: "c4"},
+			expected: global,
+			scope:    ScopeGlobal,
+		},
+		{
+			name:     "cell of another PLMN is not bound",
+			ids:      storage.IDs{NodeID: "e2:2", PlmnID: "000000", CellID: "c1"},
+			expected: global,
+			scope:    ScopeGlobal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile, scope := overrides.Resolve(test.ids, global)
+			assert.Equal(t, test.expected, profile)
+			assert.Equal(t, test.scope, scope)
+		})
+	}
+}
+
+func TestOverridesValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides Overrides
+		valid     bool
+	}{
+		{
+			name:  "empty",
+			valid: true,
+		},
+		{
+			name: "valid",
+			overrides: Overrides{
+				Profiles: map[string]Profile{"p": {TargetThreshold: 30, OverloadThreshold: 70}},
+				Cells:    []CellBinding{{CellID: "c1", Profile: "p"}},
+				Nodes:    []NodeBinding{{NodeID: "e2:1", Profile: "p"}},
+				Classes:  []ClassBinding{{Class: "small", Profile: "p"}},
+			},
+			valid: true,
+		},
+		{
+			name:      "threshold over 100",
+			overrides: Overrides{Profiles: map[string]Profile{"p": {TargetThreshold: 30, OverloadThreshold: 101}}},
+		},
+		{
+			name:      "target over overload",
+			overrides: Overrides{Profiles: map[string]Profile{"p": {TargetThreshold: 80, OverloadThreshold: 70}}},
+		},
+		{
+			name:      "cell bound to unknown profile",
+			overrides: Overrides{Cells: []CellBinding{{CellID: "c1", Profile: "p"}}},
+		},
+		{
+			name:      "E2 node bound to unknown profile",
+			overrides: Overrides{Nodes: []NodeBinding{{NodeID: "e2:1", Profile: "p"}}},
+		},
+		{
+			name:      "class bound to unknown profile",
+			overrides: Overrides{Classes: []ClassBinding{{Class: "small", Profile: "p"}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.overrides.Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.IsInvalid(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package triggerstorage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// NewStore generates a store object to save trigger states of each cell
+func NewStore() Store {
+	return &store{
+		storage: make(map[storage.IDs]State),
+	}
+}
+
+// Store includes all functions for trigger state storage
+type Store interface {
+	// Put puts the trigger state of the cell
+	Put(ctx context.Context, key storage.IDs, state State) error
+
+	// Get gets the trigger state of the cell
+	Get(ctx context.Context, key storage.IDs) (State, error)
+
+	// ListElements gets a copy of all trigger states in this store
+	ListElements(ctx context.Context) ([]State, error)
+
+	// Delete deletes the trigger state of the cell
+	Delete(ctx context.Context, key storage.IDs) error
+}
+
+type store struct {
+	storage map[storage.IDs]State
+	mu      sync.RWMutex
+}
+
+func (s *store) Put(_ context.Context, key storage.IDs, state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storage[key] = state
+	return nil
+}
+
+func (s *store) Get(_ context.Context, key storage.IDs) (State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if state, ok := s.storage[key]; ok {
+		return state, nil
+	}
+	return State{}, errors.NewNotFound("trigger state not found")
+}
+
+func (s *store) ListElements(_ context.Context) ([]State, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]State, 0, len(s.storage))
+	for _, state := range s.storage {
+		result = append(result, state)
+	}
+	return result, nil
+}
+
+func (s *store) Delete(_ context.Context, key storage.IDs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.storage, key)
+	return nil
+}
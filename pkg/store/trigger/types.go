@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package triggerstorage
+
+import (
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/storage"
+)
+
+// State is the trigger state of a cell
+type State struct {
+	Key       storage.IDs
+	Load      int
+	Overload  Trigger
+	Underload Trigger
+	Updated   time.Time
+}
+
+// Trigger is the state of a trigger condition with time-to-trigger
+type Trigger struct {
+	// Count is the number of consecutive cycles in which the entering condition holds
+	Count int
+
+	// Active is true once the entering condition held for time-to-trigger cycles, until the leaving condition holds
+	Active bool
+}
+
+// Update updates the trigger with the entering and leaving conditions in this cycle
+func (t *Trigger) Update(enter bool, leave bool, timeToTrigger int) {
+	if enter {
+		t.Count++
+	} else {
+		t.Count = 0
+	}
+	if t.Active && leave {
+		t.Active = false
+	}
+	if !t.Active && enter && t.Count >= timeToTrigger {
+		t.Active = true
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package triggerstorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerUpdate(t *testing.T) {
+	type cycle struct {
+		enter  bool
+		leave  bool
+		active bool
+	}
+	tests := []struct {
+		name          string
+		timeToTrigger int
+		cycles        []cycle
+	}{
+		{
+			name:          "active at once without time-to-trigger",
+			timeToTrigger: 0,
+			cycles: []cycle{
+				{enter: true, active: true},
+				{leave: true, active: false},
+			},
+		},
+		{
+			name:          "active after time-to-trigger cycles",
+			timeToTrigger: 3,
+			cycles: []cycle{
+				{enter: true, active: false},
+				{enter: true, active: false},
+				{enter: true, active: true},
+				{enter: true, active: true},
+			},
+		},
+		{
+			name:          "count restarts when the entering condition breaks",
+			timeToTrigger: 2,
+			cycles: []cycle{
+				{enter: true, active: false},
+				{active: false},
+				{enter: true, active: false},
+				{enter: true, active: true},
+			},
+		},
+		{
+			name:          "stays active in the hysteresis band until the leaving condition holds",
+			timeToTrigger: 1,
+			cycles: []cycle{
+				{enter: true, active: true},
+				{active: true},
+				{active: true},
+				{leave: true, active: false},
+			},
+		},
+		{
+			name:          "leaving and entering in the same cycle enters again",
+			timeToTrigger: 1,
+			cycles: []cycle{
+				{enter: true, active: true},
+				{enter: true, leave: true, active: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trigger := &Trigger{}
+			for i, c := range test.cycles {
+				trigger.Update(c.enter, c.leave, test.timeToTrigger)
+				assert.Equal(t, c.active, trigger.Active, "cycle %d", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package txn
+
+import (
+	"sync"
+)
+
+// Group coordinates the readers and writers of several stores so that a reader sees all of them in one consistent state;
+// the stores do not know the group, so every writer of the stores should update them through the group
+type Group struct {
+	mu sync.RWMutex
+}
+
+// NewGroup generates a group of stores
+func NewGroup() *Group {
+	return &Group{}
+}
+
+// Update calls the function as the only writer of the stores in the group; no view is taken while it runs.
+// The function must not call View or Update of the same group.
+func (g *Group) Update(fn func() error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return fn()
+}
+
+// View calls the function while no writer updates the stores in the group; several views may run together.
+// The function must not call Update of the same group.
+func (g *Group) View(fn func() error) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return fn()
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package txn
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupConsistentView(t *testing.T) {
+	g := NewGroup()
+	// x and y stand for two stores which are always updated together
+	x, y := 0, 0
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			_ = g.Update(func() error {
+				x++
+				y++
+				return nil
+			})
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		_ = g.View(func() error {
+			assert.Equal(t, x, y)
+			return nil
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, x)
+}
+
+func TestGroupViewsTogether(t *testing.T) {
+	g := NewGroup()
+	entered := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		_ = g.View(func() error {
+			close(entered)
+			<-done
+			return nil
+		})
+	}()
+	<-entered
+
+	// another view runs while the first one is still open
+	viewed := make(chan struct{})
+	go func() {
+		_ = g.View(func() error {
+			close(viewed)
+			return nil
+		})
+	}()
+	select {
+	case <-viewed:
+	case <-time.After(time.Second):
+		t.Fatal("view is blocked by another view")
+	}
+	close(done)
+}
+
+func TestGroupErrors(t *testing.T) {
+	g := NewGroup()
+	err := g.Update(func() error {
+		return errors.NewNotFound("not found")
+	})
+	assert.True(t, errors.IsNotFound(err))
+	err = g.View(func() error {
+		return errors.NewInvalid("invalid")
+	})
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+const (
+	// DefaultBufferSize is the number of events buffered for a watcher when no buffer size is given
+	DefaultBufferSize = 128
+)
+
+// OverflowPolicy decides what happens when an event arrives at a watcher whose buffer is full
+type OverflowPolicy int
+
+const (
+	// DropOldest drops the oldest buffered event to buffer the new event
+	DropOldest OverflowPolicy = iota
+
+	// DropNewest drops the new event
+	DropNewest
+
+	// Disconnect removes the watcher and closes its channel
+	Disconnect
+)
+
+// String returns string value of OverflowPolicy enum value
+func (p OverflowPolicy) String() string {
+	return [...]string{"DropOldest", "DropNewest", "Disconnect"}[p]
+}
+
+// Options is the set of options of a watcher
+type Options struct {
+	bufferSize int
+	policy     OverflowPolicy
+	replay     bool
+	keyFilter  func(key interface{}) bool
+	types      map[interface{}]bool
+}
+
+// Option is an option of a watcher
+type Option func(options *Options)
+
+// WithBufferSize buffers at most size events for the watcher
+func WithBufferSize(size int) Option {
+	return func(options *Options) {
+		options.bufferSize = size
+	}
+}
+
+// WithOverflowPolicy sets the policy applied when the buffer of the watcher is full
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(options *Options) {
+		options.policy = policy
+	}
+}
+
+// WithReplay sends the current state of the store to the watcher as Created events before any other event
+func WithReplay() Option {
+	return func(options *Options) {
+		options.replay = true
+	}
+}
+
+// WithKeyFilter sends only the events whose key the filter returns true for
+func WithKeyFilter(filter func(key interface{}) bool) Option {
+	return func(options *Options) {
+		options.keyFilter = filter
+	}
+}
+
+// WithTypeFilter sends only the events of the given types
+func WithTypeFilter(types ...interface{}) Option {
+	return func(options *Options) {
+		if options.types == nil {
+			options.types = make(map[interface{}]bool)
+		}
+		for _, t := range types {
+			options.types[t] = true
+		}
+	}
+}
+
+func newOptions(opts ...Option) *Options {
+	options := &Options{
+		bufferSize: DefaultBufferSize,
+		policy:     DropOldest,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.bufferSize <= 0 {
+		options.bufferSize = DefaultBufferSize
+	}
+	return options
+}
+
+func (o *Options) matches(key interface{}, eventType interface{}) bool {
+	if o.keyFilter != nil && !o.keyFilter(key) {
+		return false
+	}
+	if o.types != nil && !o.types[eventType] {
+		return false
+	}
+	return true
+}
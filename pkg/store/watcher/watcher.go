@@ -5,69 +5,190 @@
 package watcher
 
 import (
+	"context"
+	"sync"
+
 	"github.com/google/uuid"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-mlb/pkg/store/event"
-	"sync"
 )
 
+var log = logging.GetLogger()
+
 // EventChannel is the channel to report event happening
 type EventChannel chan event.Event
 
 // Watchers is the struct including all watchers
 type Watchers struct {
-	watchers map[uuid.UUID]Watcher
+	watchers map[uuid.UUID]*Watcher
 	rm       sync.RWMutex
 }
 
-// Watcher is the struct including a watcher
+// Watcher is the struct including a watcher; it buffers the events for its channel
+// and a single goroutine delivers them in order, so a slow receiver does not block the store
 type Watcher struct {
-	id uuid.UUID
-	ch chan<- event.Event
+	id      uuid.UUID
+	ch      chan<- event.Event
+	options *Options
+	mu      sync.Mutex
+	queue   []event.Event
+	// replay is the current state of the store sent before the queue; it does not count toward the buffer size
+	replay  []event.Event
+	dropped int
+	notify  chan struct{}
+	done    chan struct{}
+	once    sync.Once
 }
 
 // NewWatchers generates Watchers
 func NewWatchers() *Watchers {
 	return &Watchers{
-		watchers: make(map[uuid.UUID]Watcher),
+		watchers: make(map[uuid.UUID]*Watcher),
 	}
 }
 
-// Send sends an event for all registered watchers
+// Send sends an event for all registered watchers; it never blocks
 func (ws *Watchers) Send(event event.Event) {
 	ws.rm.RLock()
-	go func() {
-		for _, watcher := range ws.watchers {
-			watcher.ch <- event
+	overflowed := make([]uuid.UUID, 0)
+	for id, watcher := range ws.watchers {
+		if !watcher.enqueue(event) {
+			overflowed = append(overflowed, id)
 		}
-	}()
+	}
 	ws.rm.RUnlock()
+
+	for _, id := range overflowed {
+		log.Warnf("watcher %v is too slow to receive events - disconnect it", id)
+		ws.RemoveWatcher(id)
+	}
 }
 
-// AddWatcher adds a watcher
-func (ws *Watchers) AddWatcher(id uuid.UUID, ch chan<- event.Event) error {
-	ws.rm.Lock()
-	watcher := Watcher{
-		id: id,
-		ch: ch,
+// Watch adds a watcher which sends the events to the channel until the context is done or the watcher is disconnected;
+// the channel is closed when the watcher is removed. The replay function is called only if the replay option is given
+// and its events are sent before any other event; they do not count toward the buffer size, so the replay is never
+// dropped nor disconnects the watcher even if it is larger than the buffer.
+// The caller should prevent the events from being sent while Watch runs so that none of them is lost or duplicated.
+func (ws *Watchers) Watch(ctx context.Context, ch chan<- event.Event, replay func() []event.Event, opts ...Option) error {
+	options := newOptions(opts...)
+	watcher := &Watcher{
+		id:      uuid.New(),
+		ch:      ch,
+		options: options,
+		queue:   make([]event.Event, 0),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
 	}
-	ws.watchers[id] = watcher
+	if options.replay && replay != nil {
+		for _, e := range replay() {
+			if options.matches(e.Key, e.Type) {
+				watcher.replay = append(watcher.replay, e)
+			}
+		}
+		watcher.signal()
+	}
+
+	ws.rm.Lock()
+	ws.watchers[watcher.id] = watcher
 	ws.rm.Unlock()
-	return nil
 
+	go watcher.deliver()
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.RemoveWatcher(watcher.id)
+		case <-watcher.done:
+		}
+	}()
+	return nil
 }
 
-// RemoveWatcher removes a watcher
-func (ws *Watchers) RemoveWatcher(id uuid.UUID) error {
+// RemoveWatcher removes a watcher and closes its channel
+func (ws *Watchers) RemoveWatcher(id uuid.UUID) {
 	ws.rm.Lock()
-	watchers := make(map[uuid.UUID]Watcher, len(ws.watchers)-1)
-	for _, watcher := range ws.watchers {
-		if watcher.id != id {
-			watchers[id] = watcher
+	watcher, ok := ws.watchers[id]
+	delete(ws.watchers, id)
+	ws.rm.Unlock()
+	if ok {
+		watcher.stop()
+	}
+}
 
+// enqueue buffers the event if the watcher wants it; it returns false if the watcher should be disconnected
+func (w *Watcher) enqueue(e event.Event) bool {
+	if !w.options.matches(e.Key, e.Type) {
+		return true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.queue) >= w.options.bufferSize {
+		switch w.options.policy {
+		case DropNewest:
+			w.dropped++
+			return true
+		case Disconnect:
+			return false
+		default:
+			w.queue = w.queue[1:]
+			w.dropped++
 		}
 	}
-	ws.watchers = watchers
-	ws.rm.Unlock()
-	return nil
+	w.queue = append(w.queue, e)
+	w.signal()
+	return true
+}
 
+func (w *Watcher) signal() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) next() (event.Event, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.replay) > 0 {
+		e := w.replay[0]
+		w.replay = w.replay[1:]
+		return e, true
+	}
+	if len(w.queue) == 0 {
+		return event.Event{}, false
+	}
+	e := w.queue[0]
+	w.queue = w.queue[1:]
+	return e, true
+}
+
+// deliver sends the buffered events to the channel in order until the watcher stops
+func (w *Watcher) deliver() {
+	defer close(w.ch)
+	for {
+		e, ok := w.next()
+		if !ok {
+			select {
+			case <-w.notify:
+				continue
+			case <-w.done:
+				return
+			}
+		}
+		select {
+		case w.ch <- e:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *Watcher) stop() {
+	w.once.Do(func() {
+		w.mu.Lock()
+		if w.dropped > 0 {
+			log.Debugf("watcher %v dropped %d events", w.id, w.dropped)
+		}
+		w.mu.Unlock()
+		close(w.done)
+	})
 }
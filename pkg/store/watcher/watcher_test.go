@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2022-present Intel Corporation
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-mlb/pkg/store/event"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	created = "created"
+	updated = "updated"
+)
+
+func testEvents(keys ...string) []event.Event {
+	events := make([]event.Event, 0, len(keys))
+	for _, key := range keys {
+		events = append(events, event.Event{
+			Key:  key,
+			Type: updated,
+		})
+	}
+	return events
+}
+
+func eventKeys(events []event.Event) []string {
+	keys := make([]string, 0, len(events))
+	for _, e := range events {
+		keys = append(keys, e.Key.(string))
+	}
+	return keys
+}
+
+// receive receives the events until the channel is closed or no event arrives for a while
+func receive(ch <-chan event.Event) ([]event.Event, bool) {
+	events := make([]event.Event, 0)
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return events, true
+			}
+			events = append(events, e)
+		case <-time.After(100 * time.Millisecond):
+			return events, false
+		}
+	}
+}
+
+func TestOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     OverflowPolicy
+		keys       []string
+		queued     []string
+		dropped    int
+		disconnect bool
+	}{
+		{name: "drop oldest", policy: DropOldest, keys: []string{"a", "b", "c", "d"}, queued: []string{"c", "d"}, dropped: 2},
+		{name: "drop newest", policy: DropNewest, keys: []string{"a", "b", "c", "d"}, queued: []string{"a", "b"}, dropped: 2},
+		{name: "disconnect", policy: Disconnect, keys: []string{"a", "b", "c"}, queued: []string{"a", "b"}, disconnect: true},
+		{name: "within the buffer", policy: Disconnect, keys: []string{"a", "b"}, queued: []string{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the watcher is not delivering, so the events stay in its buffer
+			w := &Watcher{
+				options: newOptions(WithBufferSize(2), WithOverflowPolicy(test.policy)),
+				notify:  make(chan struct{}, 1),
+			}
+			disconnect := false
+			for _, e := range testEvents(test.keys...) {
+				if !w.enqueue(e) {
+					disconnect = true
+				}
+			}
+			assert.Equal(t, test.queued, eventKeys(w.queue))
+			assert.Equal(t, test.dropped, w.dropped)
+			assert.Equal(t, test.disconnect, disconnect)
+		})
+	}
+}
+
+func TestWatchDisconnect(t *testing.T) {
+	ws := NewWatchers()
+	ch := make(chan event.Event)
+	err := ws.Watch(context.Background(), ch, nil, WithBufferSize(1), WithOverflowPolicy(Disconnect))
+	assert.NoError(t, err)
+
+	// nobody receives, so the watcher holds one event in delivery and one in its buffer at most
+	for _, e := range testEvents("a", "b", "c") {
+		ws.Send(e)
+	}
+	_, closed := receive(ch)
+	assert.True(t, closed)
+	assert.Empty(t, ws.watchers)
+}
+
+func TestWatch(t *testing.T) {
+	replay := func() []event.Event {
+		return []event.Event{
+			{Key: "a", Type: created},
+			{Key: "b", Type: created},
+			{Key: "skip", Type: created},
+		}
+	}
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "events in order",
+			expected: []string{"c", "skip", "d"},
+		},
+		{
+			name:     "replay before the events",
+			opts:     []Option{WithReplay()},
+			expected: []string{"a", "b", "skip", "c", "skip", "d"},
+		},
+		{
+			name: "key filter",
+			opts: []Option{WithReplay(), WithKeyFilter(func(key interface{}) bool {
+				return key != "skip"
+			})},
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "type filter",
+			opts:     []Option{WithReplay(), WithTypeFilter(updated)},
+			expected: []string{"c", "skip", "d"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			ws := NewWatchers()
+			ch := make(chan event.Event)
+			err := ws.Watch(ctx, ch, replay, test.opts...)
+			assert.NoError(t, err)
+			for _, e := range testEvents("c", "skip", "d") {
+				ws.Send(e)
+			}
+			events, closed := receive(ch)
+			assert.False(t, closed)
+			assert.Equal(t, test.expected, eventKeys(events))
+
+			// the channel is closed once the context is done
+			cancel()
+			_, closed = receive(ch)
+			assert.True(t, closed)
+		})
+	}
+}
+
+func TestWatchReplayOverBuffer(t *testing.T) {
+	replay := func() []event.Event {
+		return []event.Event{
+			{Key: "a", Type: created},
+			{Key: "b", Type: created},
+			{Key: "c", Type: created},
+			{Key: "d", Type: created},
+			{Key: "e", Type: created},
+		}
+	}
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		keys     []string
+		expected []string
+	}{
+		{
+			name:     "disconnect",
+			policy:   Disconnect,
+			keys:     []string{"f", "g"},
+			expected: []string{"a", "b", "c", "d", "e", "f", "g"},
+		},
+		{
+			name:     "drop oldest drops the live events only",
+			policy:   DropOldest,
+			keys:     []string{"f", "g", "h"},
+			expected: []string{"a", "b", "c", "d", "e", "g", "h"},
+		},
+		{
+			name:     "drop newest drops the live events only",
+			policy:   DropNewest,
+			keys:     []string{"f", "g", "h"},
+			expected: []string{"a", "b", "c", "d", "e", "f", "g"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ws := NewWatchers()
+			ch := make(chan event.Event)
+			opts := []Option{WithReplay(), WithBufferSize(2), WithOverflowPolicy(test.policy)}
+			err := ws.Watch(ctx, ch, replay, opts...)
+			assert.NoError(t, err)
+
+			// nobody receives yet, so the replay larger than the buffer is still pending
+			for _, e := range testEvents(test.keys...) {
+				ws.Send(e)
+			}
+			events, closed := receive(ch)
+			assert.False(t, closed)
+			assert.Equal(t, test.expected, eventKeys(events))
+		})
+	}
+}
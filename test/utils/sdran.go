@@ -81,17 +81,13 @@ func WaitForAllOcnIncreased(ctx context.Context, t *testing.T, mgr *manager.Mana
 
 func verifyOcnIncreased(ctx context.Context, t *testing.T, store ocnstorage.Store) bool {
 	verify := true
-	ch := make(chan ocnstorage.Entry)
-	go func(ch chan ocnstorage.Entry) {
-		err := store.ListAllInnerElement(ctx, ch)
-		if err != nil {
-			close(ch)
-			t.Log(err)
-		}
-	}(ch)
+	entries, err := store.ListAllInnerElement(ctx)
+	if err != nil {
+		t.Log(err)
+	}
 
 	numElem := 0
-	for e := range ch {
+	for _, e := range entries {
 		numElem++
 		if e.Value.Value <= meastype.QOffset0dB {
 			t.Logf("Waiting until Ocn values increased; currently %s", e.Value.Value.String())
@@ -129,17 +125,13 @@ func WaitForAllOcnDecreased(ctx context.Context, t *testing.T, mgr *manager.Mana
 
 func verifyOcnDecreased(ctx context.Context, t *testing.T, store ocnstorage.Store) bool {
 	verify := true
-	ch := make(chan ocnstorage.Entry)
-	go func(ch chan ocnstorage.Entry) {
-		err := store.ListAllInnerElement(ctx, ch)
-		if err != nil {
-			close(ch)
-			t.Log(err)
-		}
-	}(ch)
+	entries, err := store.ListAllInnerElement(ctx)
+	if err != nil {
+		t.Log(err)
+	}
 
 	numElem := 0
-	for e := range ch {
+	for _, e := range entries {
 		numElem++
 		if e.Value.Value >= meastype.QOffset0dB {
 			t.Logf("Waiting until Ocn values decreased; currently %s", e.Value.Value.String())
@@ -183,18 +175,14 @@ func WaitForOcnNoChangeAfterExecMLB(ctx context.Context, t *testing.T, mgr *mana
 	}
 }
 
-func verifyRNibNumUEs(ctx context.Context, t *testing.T, numUEStore storage.Store) bool {
+func verifyRNibNumUEs(ctx context.Context, t *testing.T, numUEStore storage.MeasurementStore) bool {
 	result := 0
-	ch := make(chan *storage.Entry)
-	go func(ch chan *storage.Entry) {
-		err := numUEStore.ListElements(ctx, ch)
-		if err != nil {
-			close(ch)
-			t.Log(err)
-		}
-	}(ch)
-	for e := range ch {
-		result += e.Value.(storage.Measurement).Value
+	entries, err := numUEStore.ListElements(ctx)
+	if err != nil {
+		t.Log(err)
+	}
+	for _, e := range entries {
+		result += e.Value.Value
 	}
 
 	if result != TotalNumUEs {
@@ -204,19 +192,12 @@ func verifyRNibNumUEs(ctx context.Context, t *testing.T, numUEStore storage.Stor
 	return true
 }
 
-func verifyRNibNeighbor(ctx context.Context, t *testing.T, neighborStore storage.Store) bool {
-	result := 0
-	ch := make(chan *storage.Entry)
-	go func(ch chan *storage.Entry) {
-		err := neighborStore.ListElements(ctx, ch)
-		if err != nil {
-			close(ch)
-			t.Log(err)
-		}
-	}(ch)
-	for range ch {
-		result++
+func verifyRNibNeighbor(ctx context.Context, t *testing.T, neighborStore storage.NeighborStore) bool {
+	entries, err := neighborStore.ListElements(ctx)
+	if err != nil {
+		t.Log(err)
 	}
+	result := len(entries)
 
 	if result != TotalNumCells {
 		t.Log("Waiting until RNIB has neighbors")
@@ -227,17 +208,13 @@ func verifyRNibNeighbor(ctx context.Context, t *testing.T, neighborStore storage
 
 func verifyOcnStoreSize(ctx context.Context, t *testing.T, store ocnstorage.Store) bool {
 	verify := true
-	ch := make(chan ocnstorage.Entry)
-	go func(ch chan ocnstorage.Entry) {
-		err := store.ListAllInnerElement(ctx, ch)
-		if err != nil {
-			close(ch)
-			t.Log(err)
-		}
-	}(ch)
+	entries, err := store.ListAllInnerElement(ctx)
+	if err != nil {
+		t.Log(err)
+	}
 
 	numElem := 0
-	for c := range ch {
+	for _, c := range entries {
 		t.Logf("Received store: %v", c)
 		numElem++
 	}
@@ -250,17 +227,13 @@ func verifyOcnStoreSize(ctx context.Context, t *testing.T, store ocnstorage.Stor
 
 func verifyOcnNoChanged(ctx context.Context, t *testing.T, store ocnstorage.Store) bool {
 	verify := true
-	ch := make(chan ocnstorage.Entry)
-	go func(ch chan ocnstorage.Entry) {
-		err := store.ListAllInnerElement(ctx, ch)
-		if err != nil {
-			close(ch)
-			t.Log(err)
-		}
-	}(ch)
+	entries, err := store.ListAllInnerElement(ctx)
+	if err != nil {
+		t.Log(err)
+	}
 
 	numElem := 0
-	for e := range ch {
+	for _, e := range entries {
 		numElem++
 		if e.Value.Value != meastype.QOffset0dB {
 			t.Logf("Ocn values should be always 0dB; currently %v", e)